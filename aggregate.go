@@ -0,0 +1,90 @@
+package geohash
+
+import "math"
+
+type (
+	// Reducer folds a value of the points in a cell, create it by SumReducer, MinReducer or MaxReducer.
+	// The value func returns false to skip a point, a zero Reducer or a nil value func is ignored.
+	Reducer struct {
+		name   string
+		value  func(p *Point) (float64, bool)
+		reduce func(acc, v float64) float64
+	}
+
+	// Aggregation is the summary of the points in a geohash cell,
+	// Values holds the result of each Reducer by name, absent if no point had a value.
+	Aggregation struct {
+		Cell   string
+		Count  int
+		Values map[string]float64
+	}
+)
+
+func SumReducer(name string, value func(p *Point) (float64, bool)) Reducer {
+	return Reducer{name: name, value: value, reduce: func(acc, v float64) float64 { return acc + v }}
+}
+
+func MinReducer(name string, value func(p *Point) (float64, bool)) Reducer {
+	return Reducer{name: name, value: value, reduce: math.Min}
+}
+
+func MaxReducer(name string, value func(p *Point) (float64, bool)) Reducer {
+	return Reducer{name: name, value: value, reduce: math.Max}
+}
+
+// Aggregate groups the points under prefix by their geohash cell of precision, ordered by cell.
+// An empty prefix aggregates the whole Trie, precision must be in [len(prefix), geohashLen].
+func (t *Trie) Aggregate(prefix string, precision int, reducers ...Reducer) []*Aggregation {
+	if t == nil || t.root == nil || precision < len(prefix) || precision < 1 || precision > geohashLen {
+		return nil
+	}
+
+	t.RLock()
+	defer t.RUnlock()
+
+	start := t.root
+	if len(prefix) > 0 {
+		start = t.root.search(prefix)
+	}
+
+	res := make([]*Aggregation, 0)
+	start.descendants(prefix, precision, nil, func(cell string, n *node) {
+		a := &Aggregation{Cell: cell, Values: map[string]float64{}}
+		for _, box := range n.dfs() {
+			for _, point := range box.GetAllPoints() {
+				a.Count++
+				for _, r := range reducers {
+					if r.value == nil || r.reduce == nil {
+						continue
+					}
+					if v, ok := r.value(point); ok {
+						if acc, ok := a.Values[r.name]; ok {
+							v = r.reduce(acc, v)
+						}
+						a.Values[r.name] = v
+					}
+				}
+			}
+		}
+		if a.Count > 0 {
+			res = append(res, a)
+		}
+	})
+	return res
+}
+
+// descendants passes the descendants of the node of prefix at the depth of precision to fn, ordered by geohash.
+// A non nil visit prunes the cells it returns false for.
+func (n *node) descendants(prefix string, precision int, visit func(cell string) bool, fn func(cell string, n *node)) {
+	if n == nil || (visit != nil && len(prefix) > 0 && !visit(prefix)) {
+		return
+	}
+	if len(prefix) == precision {
+		fn(prefix, n)
+		return
+	}
+
+	for i := 0; i < len(n.children); i++ {
+		n.children[i].descendants(prefix+string(encoder[i]), precision, visit, fn)
+	}
+}
@@ -0,0 +1,75 @@
+package geohash
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestTrie_Aggregate(t1 *testing.T) {
+	t := NewTrie()
+	t.Put(NewPoint(13.361389, 38.115556, 1.0))
+	t.Put(NewPoint(121.506377, 31.245105, 2.0))
+	t.Put(NewPoint(121.4871639, 31.2388556, 4.0))
+	t.Put(NewPoint(121.506378, 31.245105, "东方明珠"))
+
+	value := func(p *Point) (float64, bool) {
+		v, ok := p.GetVal().(float64)
+		return v, ok
+	}
+	reducers := []Reducer{SumReducer("sum", value), MinReducer("min", value), MaxReducer("max", value)}
+	tests := []struct {
+		name      string
+		prefix    string
+		precision int
+		want      []*Aggregation
+	}{
+		{
+			name:      "TestTrie_Aggregate 1",
+			prefix:    "",
+			precision: 1,
+			want: []*Aggregation{
+				{Cell: "S", Count: 1, Values: map[string]float64{"sum": 1, "min": 1, "max": 1}},
+				{Cell: "W", Count: 3, Values: map[string]float64{"sum": 6, "min": 2, "max": 4}},
+			},
+		},
+		{
+			name:      "TestTrie_Aggregate 2",
+			prefix:    "WTW",
+			precision: 6,
+			want: []*Aggregation{
+				{Cell: "WTW3SW", Count: 1, Values: map[string]float64{"sum": 4, "min": 4, "max": 4}},
+				{Cell: "WTW3SZ", Count: 2, Values: map[string]float64{"sum": 2, "min": 2, "max": 2}},
+			},
+		},
+		{
+			name:      "TestTrie_Aggregate 3",
+			prefix:    "B",
+			precision: 2,
+			want:      []*Aggregation{},
+		},
+		{
+			name:      "TestTrie_Aggregate 4",
+			prefix:    "WTW",
+			precision: 2,
+			want:      nil,
+		},
+	}
+	for _, tt := range tests {
+		t1.Run(tt.name, func(t1 *testing.T) {
+			if got := t.Aggregate(tt.prefix, tt.precision, reducers...); !reflect.DeepEqual(got, tt.want) {
+				t1.Errorf("Aggregate() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTrie_Aggregate_zeroReducer(t1 *testing.T) {
+	t := NewTrie()
+	t.Put(NewPoint(121.506377, 31.245105, 2.0))
+	t.Put(NewPoint(121.506378, 31.245105, 4.0))
+
+	want := []*Aggregation{{Cell: "W", Count: 2, Values: map[string]float64{}}}
+	if got := t.Aggregate("", 1, Reducer{}, SumReducer("sum", nil)); !reflect.DeepEqual(got, want) {
+		t1.Errorf("Aggregate() = %v, want %v", got, want)
+	}
+}
@@ -1,9 +1,14 @@
 package geohash
 
-// Box is a rectangle in latitude/longitude space
+import "time"
+
+// Box is a rectangle in latitude/longitude space.
+// PointSet holds the expired points as well until they are removed, GetPointSet and GetAllPoints skip them.
 type Box struct {
 	Geohash  Geohash
 	PointSet map[string]*Point
+
+	expireAt map[string]int64 // the unix nano deadline of the points put with a TTL
 }
 
 func NewBox(geohash Geohash, pointSet map[string]*Point) *Box {
@@ -16,6 +21,15 @@ func NewBox(geohash Geohash, pointSet map[string]*Point) *Box {
 	}
 }
 
+// MakeBox is NewBox returning ErrInvalidGeohash instead of nil for an invalid geohash.
+func MakeBox(geohash Geohash, pointSet map[string]*Point) (*Box, error) {
+	box := NewBox(geohash, pointSet)
+	if box == nil {
+		return nil, ErrInvalidGeohash
+	}
+	return box, nil
+}
+
 func (b *Box) GetGeohash() Geohash {
 	if b == nil {
 		return ""
@@ -23,25 +37,61 @@ func (b *Box) GetGeohash() Geohash {
 	return b.Geohash
 }
 
+// GetPointSet returns the PointSet, or a copy of it without the expired points if there are any
 func (b *Box) GetPointSet() map[string]*Point {
 	if b == nil {
 		return nil
 	}
+
+	now := time.Now().UnixNano()
+	for key := range b.expireAt {
+		if b.expired(key, now) {
+			return b.pointSetAt(now)
+		}
+	}
 	return b.PointSet
 }
 
+// GetAllPoints returns the points of the Box except the expired ones
 func (b *Box) GetAllPoints() []*Point {
 	if b == nil || len(b.PointSet) == 0 {
 		return []*Point{}
 	}
 
+	now := time.Now().UnixNano()
 	res := make([]*Point, 0, len(b.PointSet))
-	for _, point := range b.PointSet {
-		res = append(res, point)
+	for key, point := range b.PointSet {
+		if !b.expired(key, now) {
+			res = append(res, point)
+		}
 	}
 	return res
 }
 
+// pointSetAt returns a copy of the PointSet without the points expired at now
+func (b *Box) pointSetAt(now int64) map[string]*Point {
+	res := make(map[string]*Point, len(b.PointSet))
+	for key, point := range b.PointSet {
+		if !b.expired(key, now) {
+			res[key] = point
+		}
+	}
+	return res
+}
+
+// alive reports whether any point of the Box is not expired at now
+func (b *Box) alive(now int64) bool {
+	if len(b.expireAt) < len(b.PointSet) {
+		return true
+	}
+	for key := range b.PointSet {
+		if !b.expired(key, now) {
+			return true
+		}
+	}
+	return false
+}
+
 func (b *Box) add(point *Point) {
 	if b == nil || !b.Geohash.valid() || point == nil {
 		return
@@ -51,4 +101,32 @@ func (b *Box) add(point *Point) {
 		b.PointSet = map[string]*Point{}
 	}
 	b.PointSet[point.key()] = point
+	delete(b.expireAt, point.key())
+}
+
+// remove removes the point of key from the Box
+func (b *Box) remove(key string) {
+	if b == nil {
+		return
+	}
+
+	delete(b.PointSet, key)
+	delete(b.expireAt, key)
+}
+
+// expire sets the unix nano deadline of the point of key
+func (b *Box) expire(key string, deadline int64) {
+	if b == nil {
+		return
+	}
+
+	if b.expireAt == nil {
+		b.expireAt = map[string]int64{}
+	}
+	b.expireAt[key] = deadline
+}
+
+func (b *Box) expired(key string, now int64) bool {
+	deadline, ok := b.expireAt[key]
+	return ok && deadline <= now
 }
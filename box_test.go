@@ -1,6 +1,7 @@
 package geohash
 
 import (
+	"errors"
 	"reflect"
 	"testing"
 )
@@ -61,6 +62,33 @@ func TestNewBox(t *testing.T) {
 	}
 }
 
+func TestMakeBox(t *testing.T) {
+	tests := []struct {
+		name    string
+		geohash Geohash
+		wantErr error
+	}{
+		{
+			name:    "TestMakeBox 1",
+			geohash: "A",
+			wantErr: ErrInvalidGeohash,
+		},
+		{
+			name:    "TestMakeBox 2",
+			geohash: "WTW3SZYP",
+			wantErr: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := MakeBox(tt.geohash, nil)
+			if !errors.Is(err, tt.wantErr) || (got == nil) == (err == nil) {
+				t.Errorf("MakeBox() = %v, %v, want error %v", got, err, tt.wantErr)
+			}
+		})
+	}
+}
+
 func TestBox_GetGeohash(t *testing.T) {
 	type fields struct {
 		Geohash  Geohash
@@ -254,3 +282,19 @@ func TestBox_add(t *testing.T) {
 		})
 	}
 }
+
+func TestBox_expire(t *testing.T) {
+	p1 := NewPoint(121.506377, 31.245105, "东方明珠")
+	p2 := NewPoint(121.506378, 31.245105, "东方明珠")
+	b := NewBox("WTW3SZYP", map[string]*Point{p1.key(): p1, p2.key(): p2})
+	b.expire(p1.key(), 1)
+	t.Run("TestBox_expire", func(t *testing.T) {
+		if got := b.GetAllPoints(); !reflect.DeepEqual(got, []*Point{p2}) {
+			t.Errorf("GetAllPoints() = %v, want %v", got, []*Point{p2})
+		}
+		b.add(p1)
+		if got := b.GetAllPoints(); len(got) != 2 {
+			t.Errorf("GetAllPoints() = %v, want %v", got, []*Point{p1, p2})
+		}
+	})
+}
@@ -0,0 +1,90 @@
+package geohash
+
+import "math"
+
+// clusterSampleSize is the maximum number of members sampled for a Cluster
+const clusterSampleSize = 5
+
+// Cluster summarizes the points of a geohash cell for the map display.
+type Cluster struct {
+	Cell    string
+	Center  *Point // the centroid of the points
+	Count   int
+	Bounds  Rect     // the bounding box of the points
+	Members []*Point // a sample of at most clusterSampleSize points
+}
+
+// Cluster groups the points in the viewport by the geohash cells matching the zoom level of a web map,
+// a cell holding fewer than minClusterSize points is returned as individual points instead.
+// A Cluster covers its whole cell even if the cell crosses the edge of the viewport,
+// so that clusters stay stable while the map is panned, individual points are limited to the viewport.
+// The viewport is in the datum of the Trie.
+func (t *Trie) Cluster(viewport Rect, zoom, minClusterSize int) ([]*Cluster, []*Point) {
+	if t == nil || t.root == nil || zoom < 0 {
+		return nil, nil
+	}
+
+	t.RLock()
+	defer t.RUnlock()
+
+	clusters, points := make([]*Cluster, 0), make([]*Point, 0)
+	visit := func(cell string) bool {
+		bounds, _ := Geohash(cell).Bounds()
+		return bounds.Intersects(viewport)
+	}
+	t.root.descendants("", zoomPrecision(zoom), visit, func(cell string, n *node) {
+		members := make([]*Point, 0, n.pointCount)
+		for _, box := range n.dfs() {
+			members = append(members, box.GetAllPoints()...)
+		}
+
+		if len(members) == 0 {
+			// every point of the cell has expired
+			return
+		}
+		if len(members) < minClusterSize {
+			for _, p := range members {
+				if viewport.Contains(p) {
+					points = append(points, p)
+				}
+			}
+			return
+		}
+		clusters = append(clusters, newCluster(cell, members))
+	})
+	return clusters, points
+}
+
+func newCluster(cell string, members []*Point) *Cluster {
+	c := &Cluster{
+		Cell:   cell,
+		Count:  len(members),
+		Bounds: Rect{MinLng: maxLng, MinLat: maxLat, MaxLng: minLng, MaxLat: minLat},
+	}
+
+	var sumLng, sumLat float64
+	for _, p := range members {
+		sumLng += p.Lng
+		sumLat += p.Lat
+		c.Bounds.MinLng, c.Bounds.MaxLng = math.Min(c.Bounds.MinLng, p.Lng), math.Max(c.Bounds.MaxLng, p.Lng)
+		c.Bounds.MinLat, c.Bounds.MaxLat = math.Min(c.Bounds.MinLat, p.Lat), math.Max(c.Bounds.MaxLat, p.Lat)
+	}
+	c.Center = NewPoint(sumLng/float64(c.Count), sumLat/float64(c.Count), nil)
+
+	sample := clusterSampleSize
+	if len(members) < sample {
+		sample = len(members)
+	}
+	c.Members = members[:sample:sample]
+	return c
+}
+
+// zoomPrecision returns the geohash precision whose cells are at most a quarter of a web map tile wide at zoom
+func zoomPrecision(zoom int) int {
+	for precision := 1; precision < geohashLen; precision++ {
+		if lngBits, _ := cellBits(precision); lngBits >= zoom+2 {
+			return precision
+		}
+	}
+	return geohashLen
+}
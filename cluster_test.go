@@ -0,0 +1,88 @@
+package geohash
+
+import (
+	"math"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestTrie_Cluster(t1 *testing.T) {
+	t := NewTrie()
+	palermo := NewPoint(13.361389, 38.115556, "Palermo")
+	t.Put(palermo)
+	for i := 0; i < 10; i++ {
+		t.Put(NewPoint(121.5+float64(i)/1000, 31.24, i))
+	}
+	world := Rect{MinLng: minLng, MinLat: minLat, MaxLng: maxLng, MaxLat: maxLat}
+	t1.Run("TestTrie_Cluster 1", func(t1 *testing.T) {
+		clusters, points := t.Cluster(world, 2, 2)
+		if !reflect.DeepEqual(points, []*Point{palermo}) {
+			t1.Errorf("Cluster() points = %v, want %v", points, []*Point{palermo})
+		}
+		if len(clusters) != 1 {
+			t1.Fatalf("Cluster() clusters = %v, want 1", clusters)
+		}
+		c := clusters[0]
+		if c.Cell != "WT" || c.Count != 10 || len(c.Members) != clusterSampleSize {
+			t1.Errorf("Cluster() = %+v", c)
+		}
+		if math.Abs(c.Center.Lng-121.5045) > 1e-9 || math.Abs(c.Center.Lat-31.24) > 1e-9 {
+			t1.Errorf("Cluster() center = %v", c.Center)
+		}
+		if want := (Rect{MinLng: 121.5, MinLat: 31.24, MaxLng: 121.509, MaxLat: 31.24}); c.Bounds != want {
+			t1.Errorf("Cluster() bounds = %v, want %v", c.Bounds, want)
+		}
+	})
+	t1.Run("TestTrie_Cluster 2", func(t1 *testing.T) {
+		clusters, points := t.Cluster(Rect{MinLng: 121.5035, MinLat: 31, MaxLng: 122, MaxLat: 32}, 20, 2)
+		if len(clusters) != 0 || len(points) != 6 {
+			t1.Errorf("Cluster() = %v, %v, want 6 points", clusters, points)
+		}
+	})
+	t1.Run("TestTrie_Cluster 3", func(t1 *testing.T) {
+		clusters, points := t.Cluster(Rect{MinLng: 0, MinLat: 0, MaxLng: 20, MaxLat: 40}, 2, 1)
+		if len(clusters) != 1 || clusters[0].Count != 1 || len(points) != 0 {
+			t1.Errorf("Cluster() = %v, %v, want 1 cluster", clusters, points)
+		}
+	})
+	t1.Run("TestTrie_Cluster expired", func(t1 *testing.T) {
+		t := NewTrie()
+		t.PutWithTTL(palermo, time.Nanosecond)
+		time.Sleep(time.Millisecond)
+		if clusters, points := t.Cluster(world, 2, 0); len(clusters) != 0 || len(points) != 0 {
+			t1.Errorf("Cluster() = %v, %v, want none", clusters, points)
+		}
+	})
+}
+
+func Test_zoomPrecision(t *testing.T) {
+	tests := []struct {
+		name string
+		zoom int
+		want int
+	}{
+		{
+			name: "Test_zoomPrecision 1",
+			zoom: 0,
+			want: 1,
+		},
+		{
+			name: "Test_zoomPrecision 2",
+			zoom: 10,
+			want: 5,
+		},
+		{
+			name: "Test_zoomPrecision 3",
+			zoom: 22,
+			want: geohashLen,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := zoomPrecision(tt.zoom); got != tt.want {
+				t.Errorf("zoomPrecision() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,142 @@
+package geohash
+
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
+// maxCorridorSegment is the longest route segment in meters measured in a flat projection,
+// longer segments are split along the great circle.
+const maxCorridorSegment = 10000
+
+type (
+	// corridorRegion is the set of points within width meters of a route
+	corridorRegion struct {
+		width    float64
+		segments []*corridorSegment
+	}
+
+	// corridorSegment is a leg of the route, projected to meters around its start
+	corridorSegment struct {
+		from, to *Point
+		offset   float64 // the distance along the route to from
+		length   float64
+		bounds   Rect // the rectangle enclosing the points within width of the segment
+	}
+)
+
+// GetPointsAlongRoute returns the points within width of the route,
+// ordered by their position along the route and deduplicated.
+// It returns an ErrInvalidPoint for an empty route or an invalid point, and an ErrInvalidRadius for a width <= 0.
+func (t *Trie) GetPointsAlongRoute(route []*Point, width Distance) ([]*Point, error) {
+	if t == nil || t.root == nil {
+		return nil, ErrNilTrie
+	}
+	if len(route) == 0 {
+		return nil, fmt.Errorf("%w: empty route", ErrInvalidPoint)
+	}
+	if width <= 0 {
+		return nil, ErrInvalidRadius
+	}
+	normalized := make([]*Point, len(route))
+	for i, p := range route {
+		if err := p.Validate(); err != nil {
+			return nil, err
+		}
+		normalized[i] = t.normalize(p)
+	}
+
+	c := newCorridorRegion(normalized, width.Meters())
+
+	t.RLock()
+	points := t.collect(c)
+	t.RUnlock()
+
+	positions := make(map[*Point]float64, len(points))
+	for _, p := range points {
+		_, positions[p] = c.locate(p)
+	}
+	sort.SliceStable(points, func(i, j int) bool {
+		return positions[points[i]] < positions[points[j]]
+	})
+	return points, nil
+}
+
+func newCorridorRegion(route []*Point, width float64) *corridorRegion {
+	c := &corridorRegion{width: width}
+	if len(route) == 1 {
+		route = []*Point{route[0], route[0]}
+	}
+
+	var offset float64
+	for i := 1; i < len(route); i++ {
+		pieces := int(math.Ceil(haversine(route[i-1], route[i]) / maxCorridorSegment))
+		if pieces < 1 {
+			pieces = 1
+		}
+
+		from := route[i-1]
+		for j := 1; j <= pieces; j++ {
+			to := route[i]
+			if j < pieces {
+				to = interpolate(route[i-1], route[i], float64(j)/float64(pieces))
+			}
+
+			s := &corridorSegment{from: from, to: to, offset: offset}
+			x, y := s.project(to)
+			s.length = math.Hypot(x, y)
+			s.bounds = circleRect(from, width).union(circleRect(to, width))
+			c.segments = append(c.segments, s)
+
+			offset += s.length
+			from = to
+		}
+	}
+	return c
+}
+
+func (c *corridorRegion) relate(cell Rect) relation {
+	for _, s := range c.segments {
+		if cell.Intersects(s.bounds) {
+			return intersects
+		}
+	}
+	return disjoint
+}
+
+func (c *corridorRegion) contains(p *Point) bool {
+	distance, _ := c.locate(p)
+	return distance <= c.width
+}
+
+// locate returns the distance from the point to the route and the position of its projection along the route
+func (c *corridorRegion) locate(p *Point) (distance, position float64) {
+	distance = math.Inf(1)
+	for _, s := range c.segments {
+		if !s.bounds.Contains(p) {
+			continue
+		}
+
+		x, y := s.project(p)
+		var fraction float64
+		if s.length > 0 {
+			toX, toY := s.project(s.to)
+			fraction = math.Max(0, math.Min(1, (x*toX+y*toY)/(s.length*s.length)))
+			x, y = x-fraction*toX, y-fraction*toY
+		}
+		if d := math.Hypot(x, y); d < distance {
+			distance, position = d, s.offset+fraction*s.length
+		}
+	}
+	return distance, position
+}
+
+// project returns the position of the point in meters east and north of the start of the segment,
+// in an equirectangular projection which is accurate for short segments
+func (s *corridorSegment) project(p *Point) (x, y float64) {
+	difLng := math.Mod(p.Lng-s.from.Lng+540, 360) - 180
+	x = difLng * metersPerDegree * math.Cos((s.from.Lat+s.to.Lat)/2*math.Pi/180)
+	y = (p.Lat - s.from.Lat) * metersPerDegree
+	return x, y
+}
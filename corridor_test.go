@@ -0,0 +1,98 @@
+package geohash
+
+import (
+	"errors"
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestTrie_GetPointsAlongRoute(t1 *testing.T) {
+	t := NewTrie()
+	// a route east along the equator and then north
+	route := []*Point{NewPoint(0, 0, nil), NewPoint(0.5, 0, nil), NewPoint(0.5, 0.5, nil)}
+	p1 := NewPoint(0.1, 0.001, "p1")    // ~111 m north of the first leg
+	p2 := NewPoint(0.4, -0.001, "p2")   // ~111 m south of the first leg
+	p3 := NewPoint(0.501, 0.3, "p3")    // ~111 m east of the second leg
+	p4 := NewPoint(0.499, 0.0005, "p4") // near the corner, close to both legs
+	p5 := NewPoint(0.3, 0.01, "p5")     // ~1.1 km north of the first leg
+	p6 := NewPoint(13.361389, 38.115556, "Palermo")
+	for _, p := range []*Point{p6, p5, p4, p3, p2, p1} {
+		t.Put(p)
+	}
+	tests := []struct {
+		name    string
+		route   []*Point
+		width   Distance
+		want    []*Point
+		wantErr error
+	}{
+		{
+			name:  "TestTrie_GetPointsAlongRoute 1",
+			route: route,
+			width: 200,
+			want:  []*Point{p1, p2, p4, p3},
+		},
+		{
+			name:  "TestTrie_GetPointsAlongRoute 2",
+			route: route,
+			width: 2000,
+			want:  []*Point{p1, p5, p2, p4, p3},
+		},
+		{
+			name:  "TestTrie_GetPointsAlongRoute 3",
+			route: []*Point{NewPoint(0.1, 0, nil)},
+			width: 200,
+			want:  []*Point{p1},
+		},
+		{
+			name:    "TestTrie_GetPointsAlongRoute 4",
+			route:   []*Point{NewPoint(0.1, 0, nil), nil},
+			width:   200,
+			wantErr: ErrInvalidPoint,
+		},
+		{
+			name:    "TestTrie_GetPointsAlongRoute 5",
+			route:   []*Point{NewPoint(0.1, math.NaN(), nil)},
+			width:   200,
+			wantErr: ErrInvalidPoint,
+		},
+		{
+			name:    "TestTrie_GetPointsAlongRoute 6",
+			route:   nil,
+			width:   200,
+			wantErr: ErrInvalidPoint,
+		},
+		{
+			name:    "TestTrie_GetPointsAlongRoute 7",
+			route:   route,
+			width:   0,
+			wantErr: ErrInvalidRadius,
+		},
+	}
+	for _, tt := range tests {
+		t1.Run(tt.name, func(t1 *testing.T) {
+			got, err := t.GetPointsAlongRoute(tt.route, tt.width)
+			if !errors.Is(err, tt.wantErr) {
+				t1.Fatalf("GetPointsAlongRoute() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr == nil && !reflect.DeepEqual(got, tt.want) {
+				t1.Errorf("GetPointsAlongRoute() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_newCorridorRegion(t *testing.T) {
+	c := newCorridorRegion([]*Point{NewPoint(0, 0, nil), NewPoint(0.5, 0, nil)}, 100)
+	t.Run("Test_newCorridorRegion", func(t *testing.T) {
+		// ~55.6 km is split into pieces of at most maxCorridorSegment
+		if got := len(c.segments); got != 6 {
+			t.Errorf("segments = %v, want %v", got, 6)
+		}
+		last := c.segments[len(c.segments)-1]
+		if got := last.offset + last.length; got < 55500 || got > 55700 {
+			t.Errorf("length = %v, want ~55600", got)
+		}
+	})
+}
@@ -0,0 +1,149 @@
+package geohash
+
+import "math"
+
+const (
+	// WGS84 is the datum of GPS and most of the world.
+	WGS84 Datum = iota
+	// GCJ02 is the obfuscated datum required for maps in China, used by Amap and Tencent.
+	GCJ02
+	// BD09 is the datum of Baidu, it further obfuscates GCJ02.
+	BD09
+)
+
+const (
+	krasovskyA  = 6378245.0              // semi-major axis of the Krasovsky ellipsoid used by GCJ02
+	krasovskyEE = 0.00669342162296594323 // eccentricity squared
+	bd09XPi     = math.Pi * 3000 / 180
+
+	gcj02Iterations = 30
+	gcj02Tolerance  = 1e-10
+)
+
+// Datum is the coordinate system of a Point.
+type Datum uint8
+
+func (d Datum) String() string {
+	switch d {
+	case WGS84:
+		return "WGS84"
+	case GCJ02:
+		return "GCJ02"
+	case BD09:
+		return "BD09"
+	default:
+		return "Unknown"
+	}
+}
+
+// WithDatum sets the Datum the Trie stores and queries its points in, WGS84 by default.
+// The points and query centers of other datums are converted into it, so data from mixed sources lines up.
+// Rectangles, tiles and geohash prefixes carry no datum, they are taken in the one of the Trie.
+func WithDatum(datum Datum) TrieOption {
+	return func(t *Trie) {
+		t.datum = datum
+	}
+}
+
+// ToDatum returns a copy of the point converted into datum, or the point itself if it is in datum already.
+// Outside China GCJ02 equals WGS84, BD09 does not.
+func (p *Point) ToDatum(datum Datum) *Point {
+	if p == nil || p.Datum == datum {
+		return p
+	}
+
+	lng, lat := p.Lng, p.Lat
+	switch p.Datum {
+	case WGS84:
+		lng, lat = wgs84ToGCJ02(lng, lat)
+	case BD09:
+		lng, lat = bd09ToGCJ02(lng, lat)
+	}
+	switch datum {
+	case WGS84:
+		lng, lat = gcj02ToWGS84(lng, lat)
+	case BD09:
+		lng, lat = gcj02ToBD09(lng, lat)
+	}
+	return &Point{Lng: lng, Lat: lat, Val: p.Val, Datum: datum}
+}
+
+// normalize converts the point into the datum of the Trie
+func (t *Trie) normalize(p *Point) *Point {
+	return p.ToDatum(t.datum)
+}
+
+// normalizeValid validates the point and converts it into the datum of the Trie,
+// it returns a *PointError as well if the conversion moves the point out of range, like across the antimeridian.
+func (t *Trie) normalizeValid(p *Point) (*Point, error) {
+	if err := p.Validate(); err != nil {
+		return nil, err
+	}
+	p = t.normalize(p)
+	if err := p.Validate(); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+func outOfChina(lng, lat float64) bool {
+	return lng < 72.004 || lng > 137.8347 || lat < 0.8293 || lat > 55.8271
+}
+
+func wgs84ToGCJ02(lng, lat float64) (float64, float64) {
+	if outOfChina(lng, lat) {
+		return lng, lat
+	}
+	difLng, difLat := gcj02Offset(lng, lat)
+	return lng + difLng, lat + difLat
+}
+
+// gcj02ToWGS84 inverts wgs84ToGCJ02 by fixed-point iteration, the offset changes slowly enough to converge in a few steps
+func gcj02ToWGS84(lng, lat float64) (float64, float64) {
+	if outOfChina(lng, lat) {
+		return lng, lat
+	}
+
+	wgsLng, wgsLat := lng, lat
+	for i := 0; i < gcj02Iterations; i++ {
+		gcjLng, gcjLat := wgs84ToGCJ02(wgsLng, wgsLat)
+		difLng, difLat := lng-gcjLng, lat-gcjLat
+		wgsLng, wgsLat = wgsLng+difLng, wgsLat+difLat
+		if math.Abs(difLng) < gcj02Tolerance && math.Abs(difLat) < gcj02Tolerance {
+			break
+		}
+	}
+	return wgsLng, wgsLat
+}
+
+func gcj02ToBD09(lng, lat float64) (float64, float64) {
+	z := math.Sqrt(lng*lng+lat*lat) + 0.00002*math.Sin(lat*bd09XPi)
+	theta := math.Atan2(lat, lng) + 0.000003*math.Cos(lng*bd09XPi)
+	return z*math.Cos(theta) + 0.0065, z*math.Sin(theta) + 0.006
+}
+
+func bd09ToGCJ02(lng, lat float64) (float64, float64) {
+	x, y := lng-0.0065, lat-0.006
+	z := math.Sqrt(x*x+y*y) - 0.00002*math.Sin(y*bd09XPi)
+	theta := math.Atan2(y, x) - 0.000003*math.Cos(x*bd09XPi)
+	return z * math.Cos(theta), z * math.Sin(theta)
+}
+
+// gcj02Offset returns the offset in degrees added by GCJ02 to the WGS84 coordinates
+func gcj02Offset(lng, lat float64) (difLng, difLat float64) {
+	x, y := lng-105, lat-35
+	common := (20*math.Sin(6*x*math.Pi) + 20*math.Sin(2*x*math.Pi)) * 2 / 3
+	difLat = -100 + 2*x + 3*y + 0.2*y*y + 0.1*x*y + 0.2*math.Sqrt(math.Abs(x)) + common +
+		(20*math.Sin(y*math.Pi)+40*math.Sin(y/3*math.Pi))*2/3 +
+		(160*math.Sin(y/12*math.Pi)+320*math.Sin(y*math.Pi/30))*2/3
+	difLng = 300 + x + 2*y + 0.1*x*x + 0.1*x*y + 0.1*math.Sqrt(math.Abs(x)) + common +
+		(20*math.Sin(x*math.Pi)+40*math.Sin(x/3*math.Pi))*2/3 +
+		(150*math.Sin(x/12*math.Pi)+300*math.Sin(x/30*math.Pi))*2/3
+
+	radianLat := lat / 180 * math.Pi
+	magic := 1 - krasovskyEE*math.Sin(radianLat)*math.Sin(radianLat)
+	sqrtMagic := math.Sqrt(magic)
+	difLat = difLat * 180 / ((krasovskyA * (1 - krasovskyEE)) / (magic * sqrtMagic) * math.Pi)
+	difLng = difLng * 180 / (krasovskyA / sqrtMagic * math.Cos(radianLat) * math.Pi)
+	return difLng, difLat
+}
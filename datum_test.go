@@ -0,0 +1,125 @@
+package geohash
+
+import (
+	"errors"
+	"math"
+	"testing"
+	"time"
+)
+
+func TestPoint_ToDatum(t *testing.T) {
+	tests := []struct {
+		name  string
+		p     *Point
+		datum Datum
+		want  *Point
+	}{
+		{
+			name:  "TestPoint_ToDatum 1",
+			p:     &Point{Lng: 116.404, Lat: 39.915, Datum: WGS84},
+			datum: GCJ02,
+			want:  &Point{Lng: 116.41024449916938, Lat: 39.91640428150164, Datum: GCJ02},
+		},
+		{
+			name:  "TestPoint_ToDatum 2",
+			p:     &Point{Lng: 116.404, Lat: 39.915, Datum: GCJ02},
+			datum: BD09,
+			want:  &Point{Lng: 116.41036949371029, Lat: 39.92133699351022, Datum: BD09},
+		},
+		{
+			name:  "TestPoint_ToDatum 3",
+			p:     &Point{Lng: 116.404, Lat: 39.915, Datum: BD09},
+			datum: GCJ02,
+			want:  &Point{Lng: 116.39762729119315, Lat: 39.90865673957631, Datum: GCJ02},
+		},
+		{
+			name:  "TestPoint_ToDatum 4",
+			p:     &Point{Lng: 116.41024449916938, Lat: 39.91640428150164, Datum: GCJ02},
+			datum: WGS84,
+			want:  &Point{Lng: 116.404, Lat: 39.915, Datum: WGS84},
+		},
+		{
+			name:  "TestPoint_ToDatum 5",
+			p:     &Point{Lng: 13.361389, Lat: 38.115556, Val: "Palermo", Datum: WGS84},
+			datum: GCJ02,
+			want:  &Point{Lng: 13.361389, Lat: 38.115556, Val: "Palermo", Datum: GCJ02},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.p.ToDatum(tt.datum)
+			if math.Abs(got.Lng-tt.want.Lng) > 1e-9 || math.Abs(got.Lat-tt.want.Lat) > 1e-9 || got.Val != tt.want.Val || got.Datum != tt.want.Datum {
+				t.Errorf("ToDatum() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPoint_ToDatum_roundTrip(t *testing.T) {
+	p := NewPoint(121.506377, 31.245105, "东方明珠")
+	// the inverse of BD09 is approximate to within a meter
+	for datum, tolerance := range map[Datum]Distance{GCJ02: 0.01 * Meter, BD09: Meter} {
+		t.Run("TestPoint_ToDatum_roundTrip "+datum.String(), func(t *testing.T) {
+			converted := p.ToDatum(datum)
+			if d := p.DistanceTo(converted); d < 100*Meter || d > 2*Kilometer {
+				t.Errorf("ToDatum() moved the point by %v, want hundreds of meters", d)
+			}
+			if got := converted.ToDatum(WGS84); p.DistanceTo(got) > tolerance {
+				t.Errorf("ToDatum().ToDatum() = %v, want %v", got, p)
+			}
+		})
+	}
+	t.Run("TestPoint_ToDatum_roundTrip same", func(t *testing.T) {
+		if got := p.ToDatum(WGS84); got != p {
+			t.Errorf("ToDatum() = %p, want %p", got, p)
+		}
+	})
+}
+
+func TestWithDatum(t1 *testing.T) {
+	t := NewTrie(WithDatum(GCJ02))
+	gps := NewPoint(121.506377, 31.245105, "东方明珠")
+	amap := gps.ToDatum(GCJ02)
+	baidu := gps.ToDatum(BD09)
+	baidu.Val = "百度"
+	t.Put(amap)
+	t.Put(baidu)
+	t1.Run("TestWithDatum", func(t1 *testing.T) {
+		got, err := t.GetPointsByCircle(gps, 10)
+		if err != nil {
+			t1.Fatalf("GetPointsByCircle() error = %v", err)
+		}
+		if len(got) != 2 {
+			t1.Fatalf("GetPointsByCircle() = %v, want %v points", got, 2)
+		}
+		for _, p := range got {
+			if p.Datum != GCJ02 {
+				t1.Errorf("GetPointsByCircle() datum = %v, want %v", p.Datum, GCJ02)
+			}
+		}
+		if count, _ := NewTrie().CountInCircle(gps, 10); count != 0 {
+			t1.Errorf("CountInCircle() = %v, want %v", count, 0)
+		}
+	})
+}
+
+func TestWithDatum_outOfRange(t1 *testing.T) {
+	t := NewTrie(WithDatum(BD09))
+	// BD09 shifts the longitude east everywhere, past the antimeridian here
+	p := NewPoint(179.999, 10, nil)
+	t1.Run("TestWithDatum_outOfRange", func(t1 *testing.T) {
+		if err := t.Put(p); !errors.Is(err, ErrInvalidPoint) {
+			t1.Errorf("Put() error = %v, want %v", err, ErrInvalidPoint)
+		}
+		if err := t.PutWithTTL(p, time.Hour); !errors.Is(err, ErrInvalidPoint) {
+			t1.Errorf("PutWithTTL() error = %v, want %v", err, ErrInvalidPoint)
+		}
+		t.Put(NewPoint(179, 10, nil))
+		if t.Move(NewPoint(179, 10, nil), p) {
+			t1.Errorf("Move() = %v, want %v", true, false)
+		}
+		if got := t.CountPoints(); got != 1 {
+			t1.Errorf("CountPoints() = %v, want %v", got, 1)
+		}
+	})
+}
@@ -0,0 +1,48 @@
+package geohash
+
+import (
+	"math"
+	"strconv"
+)
+
+const (
+	Meter        Distance = 1
+	Kilometer             = 1000 * Meter
+	Mile                  = 1609.344 * Meter
+	NauticalMile          = 1852 * Meter
+)
+
+// Distance is a length in meters, build it from the units like 1.5 * Kilometer.
+type Distance float64
+
+func (d Distance) Meters() float64 {
+	return float64(d)
+}
+
+func (d Distance) Kilometers() float64 {
+	return float64(d / Kilometer)
+}
+
+func (d Distance) Miles() float64 {
+	return float64(d / Mile)
+}
+
+func (d Distance) NauticalMiles() float64 {
+	return float64(d / NauticalMile)
+}
+
+// String formats the distance in meters below a kilometer and in kilometers otherwise, like "0.25m" and "12.5km".
+func (d Distance) String() string {
+	if math.Abs(float64(d)) < float64(Kilometer) {
+		return strconv.FormatFloat(math.Round(d.Meters()*100)/100, 'f', -1, 64) + "m"
+	}
+	return strconv.FormatFloat(math.Round(d.Kilometers()*1000)/1000, 'f', -1, 64) + "km"
+}
+
+// DistanceTo returns the great circle distance to target without truncating it like Distance does.
+func (p *Point) DistanceTo(target *Point) Distance {
+	if p == nil || target == nil {
+		return 0
+	}
+	return Distance(haversine(p, target))
+}
@@ -0,0 +1,108 @@
+package geohash
+
+import (
+	"math"
+	"testing"
+)
+
+func TestDistance_units(t *testing.T) {
+	tests := []struct {
+		name string
+		d    Distance
+		unit func(d Distance) float64
+		want float64
+	}{
+		{
+			name: "TestDistance_units 1",
+			d:    1500 * Meter,
+			unit: Distance.Kilometers,
+			want: 1.5,
+		},
+		{
+			name: "TestDistance_units 2",
+			d:    Mile,
+			unit: Distance.Meters,
+			want: 1609.344,
+		},
+		{
+			name: "TestDistance_units 3",
+			d:    3 * NauticalMile,
+			unit: Distance.NauticalMiles,
+			want: 3,
+		},
+		{
+			name: "TestDistance_units 4",
+			d:    10 * Kilometer,
+			unit: Distance.Miles,
+			want: 6.213711922373339,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.unit(tt.d); math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("unit() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDistance_String(t *testing.T) {
+	tests := []struct {
+		name string
+		d    Distance
+		want string
+	}{
+		{
+			name: "TestDistance_String 1",
+			d:    0.254 * Meter,
+			want: "0.25m",
+		},
+		{
+			name: "TestDistance_String 2",
+			d:    999 * Meter,
+			want: "999m",
+		},
+		{
+			name: "TestDistance_String 3",
+			d:    12.5 * Kilometer,
+			want: "12.5km",
+		},
+		{
+			name: "TestDistance_String 4",
+			d:    NauticalMile,
+			want: "1.852km",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.d.String(); got != tt.want {
+				t.Errorf("String() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPoint_DistanceTo(t *testing.T) {
+	p := NewPoint(121.506377, 31.245105, "东方明珠")
+	target := NewPoint(121.506382, 31.245105, nil)
+	t.Run("TestPoint_DistanceTo", func(t *testing.T) {
+		if got := p.DistanceTo(target); got <= 0 || got >= Meter || p.Distance(target) != 0 {
+			t.Errorf("DistanceTo() = %v, want a sub-meter distance", got)
+		}
+		if got := p.DistanceTo(nil); got != 0 {
+			t.Errorf("DistanceTo() = %v, want %v", got, 0)
+		}
+	})
+}
+
+func TestTrie_CountInCircle_subMeter(t1 *testing.T) {
+	t := NewTrie()
+	center := NewPoint(121.506377, 31.245105, "东方明珠")
+	t.Put(NewPoint(121.506380, 31.245105, nil)) // about 0.29m
+	t.Put(NewPoint(121.506385, 31.245105, nil)) // about 0.76m
+	t1.Run("TestTrie_CountInCircle_subMeter", func(t1 *testing.T) {
+		if got, _ := t.CountInCircle(center, 0.5*Meter); got != 1 {
+			t1.Errorf("CountInCircle() = %v, want %v", got, 1)
+		}
+	})
+}
@@ -0,0 +1,17 @@
+package geohash
+
+import "errors"
+
+// The errors of the public API, test them with errors.Is.
+var (
+	ErrInvalidGeohash       = errors.New("invalid geohash")
+	ErrInvalidPoint         = errors.New("invalid point")
+	ErrInvalidRadius        = errors.New("invalid radius")
+	ErrInvalidTile          = errors.New("invalid tile")
+	ErrInvalidGridReference = errors.New("invalid grid reference")
+	ErrInvalidPlusCode      = errors.New("invalid plus code")
+	ErrNotFound             = errors.New("not found")
+	// ErrNilTrie is returned by the methods of a nil or zero Trie, ShardedTrie, PersistentTrie, Snapshot,
+	// DurableTrie or GeofenceEngine.
+	ErrNilTrie = errors.New("nil trie")
+)
@@ -0,0 +1,125 @@
+package geohash
+
+import "math"
+
+const (
+	// Spherical measures the great circle distance on a sphere of earthRadius by haversine, it is fast but may err by up to 0.5%.
+	Spherical DistanceModel = iota
+	// Ellipsoidal measures the geodesic distance on the WGS84 ellipsoid by Vincenty's formulae, it is accurate to within a millimeter.
+	Ellipsoidal
+)
+
+const (
+	wgs84A = 6378137.0         // semi-major axis
+	wgs84F = 1 / 298.257223563 // flattening
+	wgs84B = wgs84A * (1 - wgs84F)
+
+	vincentyIterations = 200
+	vincentyTolerance  = 1e-12
+
+	// ellipsoidalMargin widens the spherical bounds of a query to enclose the ellipsoidal circle of the same radius
+	ellipsoidalMargin = 0.01
+)
+
+// DistanceModel is the model of the earth used to measure distances.
+type DistanceModel uint8
+
+// WithDistanceModel sets the DistanceModel used by the Trie queries to filter the points, Spherical by default.
+func WithDistanceModel(model DistanceModel) TrieOption {
+	return WithMetric(model)
+}
+
+// Distance returns the distance between p1 and p2.
+func (m DistanceModel) Distance(p1, p2 *Point) Distance {
+	if m == Ellipsoidal {
+		return p1.EllipsoidalDistance(p2)
+	}
+	return p1.DistanceTo(p2)
+}
+
+// Bound returns the rectangle enclosing the circle of radius around center.
+func (m DistanceModel) Bound(center *Point, radius Distance) Rect {
+	if m == Ellipsoidal {
+		return circleRect(center, radius.Meters()*(1+ellipsoidalMargin))
+	}
+	return circleRect(center, radius.Meters())
+}
+
+// EllipsoidalDistance returns the geodesic distance between p and target on the WGS84 ellipsoid.
+func (p *Point) EllipsoidalDistance(target *Point) Distance {
+	distance, _, _ := p.GeodesicInverse(target)
+	return distance
+}
+
+// GeodesicInverse solves the inverse geodesic problem on the WGS84 ellipsoid,
+// it returns the distance between p and target, the azimuth at p and the azimuth at target,
+// the azimuths are in degrees clockwise from the north in [0, 360).
+// Vincenty's formulae may not converge for nearly antipodal points, the great circle route is used then.
+func (p *Point) GeodesicInverse(target *Point) (distance Distance, initialAzimuth, finalAzimuth float64) {
+	if p == nil || target == nil {
+		return 0, 0, 0
+	}
+
+	meters, initialAzimuth, finalAzimuth, ok := vincenty(p, target)
+	if !ok {
+		return p.DistanceTo(target), bearing(p, target), math.Mod(bearing(target, p)+180, 360)
+	}
+	return Distance(meters), initialAzimuth, finalAzimuth
+}
+
+// vincenty solves the inverse geodesic problem by Vincenty's formulae, ok is false if they do not converge.
+// tan(U) = (1 − f) * tan(lat)
+// λ = L + (1 − C) * f * sin(α) * (σ + C * sin(σ) * (cos(2σₘ) + C * cos(σ) * (−1 + 2 * cos²(2σₘ))))
+// s = b * A * (σ − Δσ)
+func vincenty(p1, p2 *Point) (distance, initialAzimuth, finalAzimuth float64, ok bool) {
+	radianL := (p2.Lng - p1.Lng) * (math.Pi / 180)
+	u1 := math.Atan((1 - wgs84F) * math.Tan(p1.Lat*(math.Pi/180)))
+	u2 := math.Atan((1 - wgs84F) * math.Tan(p2.Lat*(math.Pi/180)))
+	sinU1, cosU1 := math.Sincos(u1)
+	sinU2, cosU2 := math.Sincos(u2)
+
+	var sinLambda, cosLambda, sinSigma, cosSigma, sigma, cosSqAlpha, cos2SigmaM float64
+	lambda := radianL
+	for i := 0; ; i++ {
+		if i == vincentyIterations {
+			return 0, 0, 0, false
+		}
+
+		sinLambda, cosLambda = math.Sincos(lambda)
+		sinSigma = math.Hypot(cosU2*sinLambda, cosU1*sinU2-sinU1*cosU2*cosLambda)
+		if sinSigma == 0 {
+			// coincident points
+			return 0, 0, 0, true
+		}
+		cosSigma = sinU1*sinU2 + cosU1*cosU2*cosLambda
+		sigma = math.Atan2(sinSigma, cosSigma)
+		sinAlpha := cosU1 * cosU2 * sinLambda / sinSigma
+		cosSqAlpha = 1 - sinAlpha*sinAlpha
+		cos2SigmaM = 0
+		if cosSqAlpha != 0 {
+			// not an equatorial line
+			cos2SigmaM = cosSigma - 2*sinU1*sinU2/cosSqAlpha
+		}
+		c := wgs84F / 16 * cosSqAlpha * (4 + wgs84F*(4-3*cosSqAlpha))
+		prev := lambda
+		lambda = radianL + (1-c)*wgs84F*sinAlpha*(sigma+c*sinSigma*(cos2SigmaM+c*cosSigma*(-1+2*cos2SigmaM*cos2SigmaM)))
+		if math.Abs(lambda-prev) <= vincentyTolerance {
+			break
+		}
+		if math.Abs(lambda) > math.Pi {
+			// nearly antipodal points
+			return 0, 0, 0, false
+		}
+	}
+
+	uSq := cosSqAlpha * (wgs84A*wgs84A - wgs84B*wgs84B) / (wgs84B * wgs84B)
+	a := 1 + uSq/16384*(4096+uSq*(-768+uSq*(320-175*uSq)))
+	b := uSq / 1024 * (256 + uSq*(-128+uSq*(74-47*uSq)))
+	deltaSigma := b * sinSigma * (cos2SigmaM + b/4*(cosSigma*(-1+2*cos2SigmaM*cos2SigmaM)-
+		b/6*cos2SigmaM*(-3+4*sinSigma*sinSigma)*(-3+4*cos2SigmaM*cos2SigmaM)))
+
+	distance = wgs84B * a * (sigma - deltaSigma)
+	initialAzimuth = math.Atan2(cosU2*sinLambda, cosU1*sinU2-sinU1*cosU2*cosLambda) * 180 / math.Pi
+	finalAzimuth = math.Atan2(cosU1*sinLambda, -sinU1*cosU2+cosU1*sinU2*cosLambda) * 180 / math.Pi
+	return distance, math.Mod(initialAzimuth+360, 360), math.Mod(finalAzimuth+360, 360), true
+}
@@ -0,0 +1,123 @@
+package geohash
+
+import (
+	"math"
+	"testing"
+)
+
+func TestPoint_GeodesicInverse(t *testing.T) {
+	tests := []struct {
+		name                         string
+		p, target                    *Point
+		distance                     Distance
+		initialAzimuth, finalAzimuth float64
+	}{
+		{
+			name:           "TestPoint_GeodesicInverse 1",
+			p:              NewPoint(144.42486789, -37.95103342, "Flinders Peak"),
+			target:         NewPoint(143.92649554, -37.65282114, "Buninyong"),
+			distance:       54972.271,
+			initialAzimuth: 306.868158,
+			finalAzimuth:   307.173631,
+		},
+		{
+			name:           "TestPoint_GeodesicInverse 2",
+			p:              NewPoint(0, 0, nil),
+			target:         NewPoint(1, 0, nil),
+			distance:       111319.491,
+			initialAzimuth: 90,
+			finalAzimuth:   90,
+		},
+		{
+			name:     "TestPoint_GeodesicInverse 3",
+			p:        NewPoint(0, 0, nil),
+			target:   NewPoint(0, 1, nil),
+			distance: 110574.389,
+		},
+		{
+			name:   "TestPoint_GeodesicInverse 4",
+			p:      NewPoint(121.506377, 31.245105, "东方明珠"),
+			target: NewPoint(121.506377, 31.245105, "东方明珠"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			distance, initialAzimuth, finalAzimuth := tt.p.GeodesicInverse(tt.target)
+			if math.Abs((distance-tt.distance).Meters()) > 1e-3 || math.Abs(initialAzimuth-tt.initialAzimuth) > 1e-5 || math.Abs(finalAzimuth-tt.finalAzimuth) > 1e-5 {
+				t.Errorf("GeodesicInverse() = %v, %v, %v, want %v, %v, %v", distance, initialAzimuth, finalAzimuth, tt.distance, tt.initialAzimuth, tt.finalAzimuth)
+			}
+		})
+	}
+}
+
+func TestPoint_GeodesicInverse_antipodal(t *testing.T) {
+	p, target := NewPoint(0, 0, nil), NewPoint(179.7, 0.5, nil)
+	t.Run("TestPoint_GeodesicInverse_antipodal", func(t *testing.T) {
+		if distance, _, _ := p.GeodesicInverse(target); math.IsNaN(distance.Meters()) || distance < 19900000 || distance > 20100000 {
+			t.Errorf("GeodesicInverse() = %v, want about %v", distance, p.DistanceTo(target))
+		}
+	})
+}
+
+func TestDistanceModel_Distance(t *testing.T) {
+	p, target := NewPoint(121.506377, 31.245105, "东方明珠"), NewPoint(121.4737, 31.2304, "人民广场")
+	t.Run("TestDistanceModel_Distance", func(t *testing.T) {
+		if got := Spherical.Distance(p, target); got != p.DistanceTo(target) {
+			t.Errorf("Spherical.Distance() = %v, want %v", got, p.DistanceTo(target))
+		}
+		if got := Ellipsoidal.Distance(p, target); got != p.EllipsoidalDistance(target) {
+			t.Errorf("Ellipsoidal.Distance() = %v, want %v", got, p.EllipsoidalDistance(target))
+		}
+	})
+}
+
+func TestWithDistanceModel(t1 *testing.T) {
+	// a degree of longitude on the equator is longer on the ellipsoid, and a degree of latitude is shorter
+	center, east, north := NewPoint(0, 0, nil), NewPoint(0.1, 0, "east"), NewPoint(0, 0.1, "north")
+	tests := []struct {
+		name   string
+		model  DistanceModel
+		radius uint32
+		want   map[*Point]struct{}
+	}{
+		{
+			name:   "TestWithDistanceModel 1",
+			model:  Spherical,
+			radius: 11125,
+			want:   map[*Point]struct{}{east: {}, north: {}},
+		},
+		{
+			name:   "TestWithDistanceModel 2",
+			model:  Ellipsoidal,
+			radius: 11125,
+			want:   map[*Point]struct{}{north: {}},
+		},
+		{
+			name:   "TestWithDistanceModel 3",
+			model:  Spherical,
+			radius: 11100,
+			want:   map[*Point]struct{}{},
+		},
+		{
+			name:   "TestWithDistanceModel 4",
+			model:  Ellipsoidal,
+			radius: 11100,
+			want:   map[*Point]struct{}{north: {}},
+		},
+	}
+	for _, tt := range tests {
+		t := NewTrie(WithDistanceModel(tt.model))
+		t.Put(east)
+		t.Put(north)
+		t1.Run(tt.name, func(t1 *testing.T) {
+			got, err := t.GetPointsByCircle(center, tt.radius)
+			if err != nil {
+				t1.Fatalf("GetPointsByCircle() error = %v", err)
+			}
+			assertSamePoints(t1, "GetPointsByCircle()", got, tt.want)
+			if count, _ := t.CountInCircle(center, Distance(tt.radius)); count != uint32(len(tt.want)) {
+				t1.Errorf("CountInCircle() = %v, want %v", count, len(tt.want))
+			}
+		})
+	}
+}
@@ -0,0 +1,292 @@
+package geohash
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+	"time"
+)
+
+// maxFenceCells bounds the number of cells indexing one fence,
+// a fence is indexed at the finest precision it fits in.
+const maxFenceCells = 64
+
+const (
+	FenceEnter FenceEventType = iota + 1
+	FenceExit
+	FenceDwell
+)
+
+type (
+	FenceEventType uint8
+
+	// FenceEvent reports that an object entered, exited or dwelled in a fence.
+	FenceEvent struct {
+		Type     FenceEventType
+		ObjectID string
+		FenceID  string
+		Point    *Point
+		Time     time.Time
+	}
+
+	// GeofenceEngine tracks objects against circular and polygonal fences.
+	// Fences are indexed by their covering geohash cells, so an update only tests the fences near the object.
+	GeofenceEngine struct {
+		dwell   time.Duration
+		fences  map[string]*fence
+		cells   map[string]map[string]*fence         // geohash cell of any precision -> fence id -> fence
+		objects map[string]map[string]*fencePresence // object id -> fence id -> presence
+
+		sync.Mutex
+	}
+
+	fence struct {
+		id       string
+		cells    []string
+		contains func(p *Point) bool
+	}
+
+	fencePresence struct {
+		since   time.Time
+		dwelled bool
+	}
+)
+
+func (e FenceEventType) String() string {
+	switch e {
+	case FenceEnter:
+		return "Enter"
+	case FenceExit:
+		return "Exit"
+	case FenceDwell:
+		return "Dwell"
+	default:
+		return "Unknown"
+	}
+}
+
+// NewGeofenceEngine creates a GeofenceEngine reporting FenceDwell once an object stays in a fence for dwell,
+// a dwell <= 0 disables FenceDwell.
+func NewGeofenceEngine(dwell time.Duration) *GeofenceEngine {
+	return &GeofenceEngine{
+		dwell:   dwell,
+		fences:  map[string]*fence{},
+		cells:   map[string]map[string]*fence{},
+		objects: map[string]map[string]*fencePresence{},
+	}
+}
+
+// AddCircleFence adds or replaces the fence of id with the circle of radius around center,
+// it returns an ErrInvalidPoint for an invalid center and an ErrInvalidRadius for a radius <= 0.
+func (e *GeofenceEngine) AddCircleFence(id string, center *Point, radius Distance) error {
+	if e == nil {
+		return ErrNilTrie
+	}
+	if err := center.Validate(); err != nil {
+		return err
+	}
+	if radius <= 0 {
+		return ErrInvalidRadius
+	}
+
+	e.addFence(id, circleRect(center, radius.Meters()), func(p *Point) bool {
+		return center.DistanceTo(p) <= radius
+	})
+	return nil
+}
+
+// AddPolygonFence adds or replaces the fence of id with the polygon of vertices, which is closed implicitly.
+// Every edge takes the shorter way in longitude, so an edge longer than 180° crosses the antimeridian.
+// It returns an ErrInvalidPoint for an invalid vertex or fewer than 3 vertices.
+func (e *GeofenceEngine) AddPolygonFence(id string, vertices []*Point) error {
+	if e == nil {
+		return ErrNilTrie
+	}
+	if len(vertices) < 3 {
+		return fmt.Errorf("%w: %d vertices", ErrInvalidPoint, len(vertices))
+	}
+
+	// unwrap the longitudes, so that the ray casting never sees an edge across the antimeridian
+	polygon := make([]*Point, len(vertices))
+	r := Rect{MinLng: math.Inf(1), MinLat: maxLat, MaxLng: math.Inf(-1), MaxLat: minLat}
+	for i, v := range vertices {
+		if err := v.Validate(); err != nil {
+			return err
+		}
+		lng := v.Lng
+		if i > 0 {
+			difLng := lngSpan(polygon[i-1].Lng, v.Lng)
+			if difLng >= 180 {
+				difLng -= maxLng - minLng
+			}
+			lng = polygon[i-1].Lng + difLng
+		}
+		polygon[i] = NewPoint(lng, v.Lat, nil)
+		r.MinLng, r.MaxLng = math.Min(r.MinLng, lng), math.Max(r.MaxLng, lng)
+		r.MinLat, r.MaxLat = math.Min(r.MinLat, v.Lat), math.Max(r.MaxLat, v.Lat)
+	}
+
+	// shift the polygon for its west edge to be a longitude, the east one may then be past 180°
+	shift := WrapLng(r.MinLng) - r.MinLng
+	for _, v := range polygon {
+		v.Lng += shift
+	}
+	r.MinLng, r.MaxLng = r.MinLng+shift, r.MaxLng+shift
+	west := r.MinLng
+	switch {
+	case r.MaxLng-r.MinLng >= maxLng-minLng:
+		r.MinLng, r.MaxLng = minLng, maxLng
+	case r.MaxLng > maxLng:
+		r.MaxLng -= maxLng - minLng
+	}
+
+	e.addFence(id, r, func(p *Point) bool {
+		lng := p.Lng
+		if lng < west {
+			lng += maxLng - minLng
+		}
+		return polygonContains(polygon, NewPoint(lng, p.Lat, nil))
+	})
+	return nil
+}
+
+// RemoveFence removes the fence of id without reporting FenceExit for the objects in it.
+func (e *GeofenceEngine) RemoveFence(id string) bool {
+	if e == nil {
+		return false
+	}
+
+	e.Lock()
+	defer e.Unlock()
+
+	return e.removeFence(id)
+}
+
+// Update moves the object to point at the time, and returns the events it causes:
+// FenceExit first, then FenceEnter and FenceDwell, each ordered by fence id.
+func (e *GeofenceEngine) Update(objectID string, point *Point, at time.Time) []FenceEvent {
+	if e == nil || point == nil {
+		return nil
+	}
+
+	e.Lock()
+	defer e.Unlock()
+
+	inside := map[string]struct{}{}
+	geohash := string(point.Geohash())
+	for i := 1; i <= geohashLen; i++ {
+		for id, f := range e.cells[geohash[:i]] {
+			if f.contains(point) {
+				inside[id] = struct{}{}
+			}
+		}
+	}
+
+	presences := e.objects[objectID]
+	if presences == nil {
+		presences = map[string]*fencePresence{}
+	}
+
+	var exits, enters, dwells []string
+	for id := range presences {
+		if _, ok := inside[id]; !ok {
+			exits = append(exits, id)
+			delete(presences, id)
+		}
+	}
+	for id := range inside {
+		presence, ok := presences[id]
+		if !ok {
+			enters = append(enters, id)
+			presences[id] = &fencePresence{since: at}
+			continue
+		}
+		if e.dwell > 0 && !presence.dwelled && at.Sub(presence.since) >= e.dwell {
+			dwells = append(dwells, id)
+			presence.dwelled = true
+		}
+	}
+
+	if len(presences) == 0 {
+		delete(e.objects, objectID)
+	} else {
+		e.objects[objectID] = presences
+	}
+
+	res := make([]FenceEvent, 0, len(exits)+len(enters)+len(dwells))
+	for _, group := range []struct {
+		typ FenceEventType
+		ids []string
+	}{{FenceExit, exits}, {FenceEnter, enters}, {FenceDwell, dwells}} {
+		sort.Strings(group.ids)
+		for _, id := range group.ids {
+			res = append(res, FenceEvent{Type: group.typ, ObjectID: objectID, FenceID: id, Point: point, Time: at})
+		}
+	}
+	return res
+}
+
+// RemoveObject forgets the object without reporting FenceExit.
+func (e *GeofenceEngine) RemoveObject(objectID string) {
+	if e == nil {
+		return
+	}
+
+	e.Lock()
+	defer e.Unlock()
+
+	delete(e.objects, objectID)
+}
+
+func (e *GeofenceEngine) addFence(id string, bounds Rect, contains func(p *Point) bool) {
+	precision := 1
+	for precision < geohashLen && bounds.coverCount(precision+1) <= maxFenceCells {
+		precision++
+	}
+	f := &fence{id: id, cells: bounds.cover(precision), contains: contains}
+
+	e.Lock()
+	defer e.Unlock()
+
+	e.removeFence(id)
+	e.fences[id] = f
+	for _, cell := range f.cells {
+		if e.cells[cell] == nil {
+			e.cells[cell] = map[string]*fence{}
+		}
+		e.cells[cell][id] = f
+	}
+}
+
+func (e *GeofenceEngine) removeFence(id string) bool {
+	f, ok := e.fences[id]
+	if !ok {
+		return false
+	}
+
+	delete(e.fences, id)
+	for _, cell := range f.cells {
+		delete(e.cells[cell], id)
+		if len(e.cells[cell]) == 0 {
+			delete(e.cells, cell)
+		}
+	}
+	for _, presences := range e.objects {
+		delete(presences, id)
+	}
+	return true
+}
+
+// polygonContains tests the point against the polygon by ray casting in longitude/latitude space
+func polygonContains(polygon []*Point, p *Point) bool {
+	inside := false
+	for i, j := 0, len(polygon)-1; i < len(polygon); j, i = i, i+1 {
+		a, b := polygon[i], polygon[j]
+		if (a.Lat > p.Lat) != (b.Lat > p.Lat) &&
+			p.Lng < (b.Lng-a.Lng)*(p.Lat-a.Lat)/(b.Lat-a.Lat)+a.Lng {
+			inside = !inside
+		}
+	}
+	return inside
+}
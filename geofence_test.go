@@ -0,0 +1,226 @@
+package geohash
+
+import (
+	"errors"
+	"math"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestGeofenceEngine_Update(t1 *testing.T) {
+	e := NewGeofenceEngine(time.Minute)
+	bund := NewPoint(121.4871639, 31.2388556, "上海和平饭店")
+	if err := e.AddCircleFence("bund", bund, 500); err != nil {
+		t1.Fatalf("AddCircleFence() error = %v", err)
+	}
+	if err := e.AddPolygonFence("lujiazui", []*Point{
+		NewPoint(121.495, 31.235, nil),
+		NewPoint(121.515, 31.235, nil),
+		NewPoint(121.515, 31.255, nil),
+		NewPoint(121.495, 31.255, nil),
+	}); err != nil {
+		t1.Fatalf("AddPolygonFence() error = %v", err)
+	}
+
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	tower := NewPoint(121.506377, 31.245105, "东方明珠")
+	far := NewPoint(13.361389, 38.115556, "Palermo")
+	tests := []struct {
+		name  string
+		point *Point
+		at    time.Time
+		want  []FenceEvent
+	}{
+		{
+			name:  "TestGeofenceEngine_Update 1",
+			point: bund,
+			at:    start,
+			want:  []FenceEvent{{Type: FenceEnter, ObjectID: "car", FenceID: "bund", Point: bund, Time: start}},
+		},
+		{
+			name:  "TestGeofenceEngine_Update 2",
+			point: tower,
+			at:    start.Add(time.Second),
+			want: []FenceEvent{
+				{Type: FenceExit, ObjectID: "car", FenceID: "bund", Point: tower, Time: start.Add(time.Second)},
+				{Type: FenceEnter, ObjectID: "car", FenceID: "lujiazui", Point: tower, Time: start.Add(time.Second)},
+			},
+		},
+		{
+			name:  "TestGeofenceEngine_Update 3",
+			point: tower,
+			at:    start.Add(time.Minute),
+			want:  []FenceEvent{},
+		},
+		{
+			name:  "TestGeofenceEngine_Update 4",
+			point: tower,
+			at:    start.Add(time.Minute + time.Second),
+			want:  []FenceEvent{{Type: FenceDwell, ObjectID: "car", FenceID: "lujiazui", Point: tower, Time: start.Add(time.Minute + time.Second)}},
+		},
+		{
+			name:  "TestGeofenceEngine_Update 5",
+			point: tower,
+			at:    start.Add(time.Hour),
+			want:  []FenceEvent{},
+		},
+		{
+			name:  "TestGeofenceEngine_Update 6",
+			point: far,
+			at:    start.Add(time.Hour),
+			want:  []FenceEvent{{Type: FenceExit, ObjectID: "car", FenceID: "lujiazui", Point: far, Time: start.Add(time.Hour)}},
+		},
+	}
+	for _, tt := range tests {
+		t1.Run(tt.name, func(t1 *testing.T) {
+			if got := e.Update("car", tt.point, tt.at); !reflect.DeepEqual(got, tt.want) {
+				t1.Errorf("Update() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGeofenceEngine_Update_antimeridian(t1 *testing.T) {
+	e := NewGeofenceEngine(0)
+	if err := e.AddCircleFence("circle", NewPoint(179.9995, 0, nil), Kilometer); err != nil {
+		t1.Fatalf("AddCircleFence() error = %v", err)
+	}
+	if err := e.AddPolygonFence("polygon", []*Point{
+		NewPoint(179, 10, nil),
+		NewPoint(-179, 10, nil),
+		NewPoint(-179, 11, nil),
+		NewPoint(179, 11, nil),
+	}); err != nil {
+		t1.Fatalf("AddPolygonFence() error = %v", err)
+	}
+
+	at := time.Now()
+	tests := []struct {
+		name  string
+		point *Point
+		want  []string
+	}{
+		{name: "TestGeofenceEngine_Update_antimeridian 1", point: NewPoint(-179.999, 0, nil), want: []string{"circle"}},
+		{name: "TestGeofenceEngine_Update_antimeridian 2", point: NewPoint(179.999, 0, nil), want: []string{"circle"}},
+		{name: "TestGeofenceEngine_Update_antimeridian 3", point: NewPoint(-179.5, 10.5, nil), want: []string{"polygon"}},
+		{name: "TestGeofenceEngine_Update_antimeridian 4", point: NewPoint(179.5, 10.5, nil), want: []string{"polygon"}},
+		{name: "TestGeofenceEngine_Update_antimeridian 5", point: NewPoint(0, 10.5, nil), want: nil},
+	}
+	for _, tt := range tests {
+		t1.Run(tt.name, func(t1 *testing.T) {
+			var got []string
+			for _, event := range e.Update(tt.name, tt.point, at) {
+				got = append(got, event.FenceID)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t1.Errorf("Update() fences = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGeofenceEngine_RemoveFence(t1 *testing.T) {
+	e := NewGeofenceEngine(0)
+	tower := NewPoint(121.506377, 31.245105, "东方明珠")
+	_ = e.AddCircleFence("tower", tower, 100)
+	e.Update("car", tower, time.Now())
+	t1.Run("TestGeofenceEngine_RemoveFence", func(t1 *testing.T) {
+		if got := e.RemoveFence("tower"); got != true {
+			t1.Errorf("RemoveFence() = %v, want %v", got, true)
+		}
+		if got := e.RemoveFence("tower"); got != false {
+			t1.Errorf("RemoveFence() = %v, want %v", got, false)
+		}
+		if got := e.Update("car", tower, time.Now()); len(got) != 0 {
+			t1.Errorf("Update() = %v, want %v", got, []FenceEvent{})
+		}
+		if len(e.cells) != 0 {
+			t1.Errorf("cells = %v, want empty", e.cells)
+		}
+	})
+}
+
+func TestGeofenceEngine_AddCircleFence(t *testing.T) {
+	tests := []struct {
+		name    string
+		center  *Point
+		radius  Distance
+		wantErr error
+	}{
+		{name: "TestGeofenceEngine_AddCircleFence 1", center: NewPoint(0, 0, nil), radius: 100, wantErr: nil},
+		{name: "TestGeofenceEngine_AddCircleFence 2", center: NewPoint(math.NaN(), 0, nil), radius: 100, wantErr: ErrInvalidPoint},
+		{name: "TestGeofenceEngine_AddCircleFence 3", center: nil, radius: 100, wantErr: ErrInvalidPoint},
+		{name: "TestGeofenceEngine_AddCircleFence 4", center: NewPoint(0, 0, nil), radius: 0, wantErr: ErrInvalidRadius},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := NewGeofenceEngine(0).AddCircleFence("fence", tt.center, tt.radius); !errors.Is(err, tt.wantErr) {
+				t.Errorf("AddCircleFence() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestGeofenceEngine_AddPolygonFence(t *testing.T) {
+	tests := []struct {
+		name     string
+		vertices []*Point
+		wantErr  error
+	}{
+		{
+			name:     "TestGeofenceEngine_AddPolygonFence 1",
+			vertices: []*Point{NewPoint(0, 0, nil), NewPoint(1, 0, nil)},
+			wantErr:  ErrInvalidPoint,
+		},
+		{
+			name:     "TestGeofenceEngine_AddPolygonFence 2",
+			vertices: []*Point{NewPoint(0, 0, nil), NewPoint(1, 0, nil), nil},
+			wantErr:  ErrInvalidPoint,
+		},
+		{
+			name:     "TestGeofenceEngine_AddPolygonFence 3",
+			vertices: []*Point{NewPoint(0, 0, nil), NewPoint(1, 0, nil), NewPoint(0, 1, nil)},
+			wantErr:  nil,
+		},
+		{
+			name:     "TestGeofenceEngine_AddPolygonFence 4",
+			vertices: []*Point{NewPoint(0, 0, nil), NewPoint(1, math.NaN(), nil), NewPoint(0, 1, nil)},
+			wantErr:  ErrInvalidPoint,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := NewGeofenceEngine(0).AddPolygonFence("fence", tt.vertices); !errors.Is(err, tt.wantErr) {
+				t.Errorf("AddPolygonFence() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_polygonContains(t *testing.T) {
+	triangle := []*Point{NewPoint(0, 0, nil), NewPoint(2, 0, nil), NewPoint(0, 2, nil)}
+	tests := []struct {
+		name string
+		p    *Point
+		want bool
+	}{
+		{
+			name: "Test_polygonContains 1",
+			p:    NewPoint(0.5, 0.5, nil),
+			want: true,
+		},
+		{
+			name: "Test_polygonContains 2",
+			p:    NewPoint(1.5, 1.5, nil),
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := polygonContains(triangle, tt.p); got != tt.want {
+				t.Errorf("polygonContains() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
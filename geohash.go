@@ -1,7 +1,6 @@
 package geohash
 
 import (
-	"errors"
 	"fmt"
 	"math"
 	"strconv"
@@ -26,8 +25,7 @@ const (
 )
 
 const (
-	earthRadius   = 6371000
-	coordinateToM = 111000 // estimated value of latitude and longitude converted into meters
+	earthRadius = 6371000
 )
 
 var (
@@ -38,11 +36,50 @@ var (
 	decoder = map[byte]uint8{'0': 0, '1': 1, '2': 2, '3': 3, '4': 4, '5': 5, '6': 6, '7': 7, '8': 8, '9': 9,
 		'B': 10, 'C': 11, 'D': 12, 'E': 13, 'F': 14, 'G': 15, 'H': 16, 'J': 17, 'K': 18, 'M': 19, 'N': 20,
 		'P': 21, 'Q': 22, 'R': 23, 'S': 24, 'T': 25, 'U': 26, 'V': 27, 'W': 28, 'X': 29, 'Y': 30, 'Z': 31}
+)
+
+// ErrInvalidDiameter was returned for a circle too large to be covered by geohash cells, it is an ErrInvalidRadius.
+//
+// Deprecated: the circle queries accept any radius and no longer return it.
+var ErrInvalidDiameter = fmt.Errorf("invalid diameter: %w", ErrInvalidRadius)
+
+// Direction is a compass direction to a neighbor cell
+type Direction uint8
 
-	incircleDiameterRank = [geohashLen]uint32{4992600, 624100, 156000, 19500, 4900, 609, 152, 19}
+const (
+	North Direction = iota
+	NorthEast
+	East
+	SouthEast
+	South
+	SouthWest
+	West
+	NorthWest
+
+	directionCount
 )
 
-var ErrInvalidDiameter = errors.New("invalid diameter")
+// offset returns the steps of the longitude and latitude indexes of a cell in the direction
+func (d Direction) offset() (difLng, difLat int64) {
+	switch d {
+	case North:
+		return 0, 1
+	case NorthEast:
+		return 1, 1
+	case East:
+		return 1, 0
+	case SouthEast:
+		return 1, -1
+	case South:
+		return 0, -1
+	case SouthWest:
+		return -1, -1
+	case West:
+		return -1, 0
+	default:
+		return -1, 1
+	}
+}
 
 type Geohash string
 
@@ -59,9 +96,56 @@ func (g Geohash) valid() bool {
 	return true
 }
 
+// Bounds returns the rectangle of the geohash, which may also be a prefix shorter than geohashLen
+func (g Geohash) Bounds() (Rect, bool) {
+	lngIndex, latIndex, ok := cellIndex(string(g))
+	if !ok {
+		return Rect{}, false
+	}
+
+	lngBits, latBits := cellBits(len(g))
+	width := float64(maxLng-minLng) / float64(uint32(1)<<lngBits)
+	height := float64(maxLat-minLat) / float64(uint32(1)<<latBits)
+	return Rect{
+		MinLng: minLng + float64(lngIndex)*width,
+		MinLat: minLat + float64(latIndex)*height,
+		MaxLng: minLng + float64(lngIndex+1)*width,
+		MaxLat: minLat + float64(latIndex+1)*height,
+	}, true
+}
+
+// Neighbor returns the adjacent cell of the same precision in the direction,
+// it wraps around the antimeridian and returns false beyond the poles.
+func (g Geohash) Neighbor(direction Direction) (Geohash, bool) {
+	lngIndex, latIndex, ok := cellIndex(string(g))
+	if !ok || direction >= directionCount {
+		return "", false
+	}
+
+	lngBits, latBits := cellBits(len(g))
+	lngCells, latCells := int64(1)<<lngBits, int64(1)<<latBits
+	difLng, difLat := direction.offset()
+	lng := (int64(lngIndex) + difLng + lngCells) % lngCells
+	lat := int64(latIndex) + difLat
+	if lat < 0 || lat >= latCells {
+		return "", false
+	}
+	return Geohash(cellGeohash(uint32(lng), uint32(lat), len(g))), true
+}
+
+// Neighbors returns the adjacent cells indexed by Direction, the ones beyond the poles are empty.
+func (g Geohash) Neighbors() [directionCount]Geohash {
+	var res [directionCount]Geohash
+	for d := North; d < directionCount; d++ {
+		res[d], _ = g.Neighbor(d)
+	}
+	return res
+}
+
 type Point struct {
 	Lng, Lat float64
 	Val      any
+	Datum    Datum // the datum of the coordinates, WGS84 by default
 }
 
 func NewPoint(lng, lat float64, val any) *Point {
@@ -100,6 +184,37 @@ func (p *Point) Distance(target *Point) uint32 {
 	return uint32(haversine(p, target))
 }
 
+// Bearing returns the initial bearing of the great circle route to target, in degrees clockwise from the north in [0, 360).
+func (p *Point) Bearing(target *Point) float64 {
+	if p == nil || target == nil {
+		return 0
+	}
+	return bearing(p, target)
+}
+
+// Destination returns the point reached by traveling distance along the great circle of the initial bearing,
+// in degrees clockwise from the north.
+func (p *Point) Destination(distance Distance, bearing float64) *Point {
+	if p == nil {
+		return nil
+	}
+	return destination(p, distance.Meters(), bearing)
+}
+
+// Midpoint returns the point halfway along the great circle route to target.
+func (p *Point) Midpoint(target *Point) *Point {
+	return p.Interpolate(target, 0.5)
+}
+
+// Interpolate returns the point at the fraction of the great circle route to target,
+// 0 is p and 1 is target.
+func (p *Point) Interpolate(target *Point, fraction float64) *Point {
+	if p == nil || target == nil {
+		return nil
+	}
+	return interpolate(p, target, fraction)
+}
+
 // Geohash converts the longitude and latitude into corresponding fixed 40-bit geohash strings,
 // 5 bits is mapped by one base32, so it consists of a total of 8 base32 characters.
 func (p *Point) Geohash() Geohash {
@@ -135,39 +250,6 @@ func (p *Point) key() string {
 	return fmt.Sprintf("%v_%v", p.Lng, p.Lat)
 }
 
-// circumscribedSquarePointsByCircle return the circumscribed square of the circle with point and radius
-func (p *Point) circumscribedSquarePointsByCircle(radius uint32) [9]*Point {
-	dif := float64(radius) / coordinateToM
-	left := p.Lng - dif
-	if left < minLng {
-		left += maxLng << 1
-	}
-	right := p.Lng + dif
-	if right > maxLng {
-		right -= maxLng << 1
-	}
-	bot := p.Lat - dif
-	if bot < minLat {
-		bot += maxLat << 1
-	}
-	top := p.Lat + dif
-	if top > maxLat {
-		top -= maxLat << 1
-	}
-
-	return [9]*Point{
-		NewPoint(left, top, nil),
-		NewPoint(p.Lng, top, nil),
-		NewPoint(right, top, nil),
-		NewPoint(left, p.Lat, nil),
-		NewPoint(p.Lng, p.Lat, nil),
-		NewPoint(right, p.Lat, nil),
-		NewPoint(left, bot, nil),
-		NewPoint(p.Lng, bot, nil),
-		NewPoint(right, bot, nil),
-	}
-}
-
 // encode converts the latitude or longitude coordinate into corresponding fixed 20-bit binary string
 func encode(coordinate, start, end float64) string {
 	bits := strings.Builder{}
@@ -193,23 +275,6 @@ func decode(bit byte) uint8 {
 	return invalidCode
 }
 
-// getGeohashLenByDiameter returns the minimum length of geohash required by the circumscribed rectangle,
-// according to the diameter of the circle
-// diameter <= incircleDiameterRank[2]
-func getGeohashLenByDiameter(diameter uint32) (uint8, error) {
-	if diameter == 0 || diameter > incircleDiameterRank[2] {
-		return 0, ErrInvalidDiameter
-	}
-
-	for i := geohashLen - 1; i >= 0; i-- {
-		if incircleDiameterRank[i] >= diameter {
-			return uint8(i) + 1, nil
-		}
-	}
-
-	return 0, ErrInvalidDiameter
-}
-
 // haversine formula is used to calculate the distance of large circle route between two latitude and longitude coordinates.
 // a = sin²((lat₂ - lat₁)/2) + cos(lat₁) * cos(lat₂) * sin²((lng₂ - lng₁)/2)
 // c = 2 * atan2(√a, √(1−a))
@@ -232,3 +297,62 @@ func haversine(p1, p2 *Point) float64 {
 
 	return d
 }
+
+// bearing returns the initial bearing of the great circle route from p1 to p2,
+// in degrees clockwise from the north in [0, 360).
+// θ = atan2(sin(lng₂ - lng₁) * cos(lat₂), cos(lat₁) * sin(lat₂) − sin(lat₁) * cos(lat₂) * cos(lng₂ - lng₁))
+func bearing(p1, p2 *Point) float64 {
+	if p1 == nil || p2 == nil {
+		return 0
+	}
+
+	radianLat1 := p1.Lat * (math.Pi / 180)
+	radianLat2 := p2.Lat * (math.Pi / 180)
+	radianDifLng := (p2.Lng - p1.Lng) * (math.Pi / 180)
+
+	y := math.Sin(radianDifLng) * math.Cos(radianLat2)
+	x := math.Cos(radianLat1)*math.Sin(radianLat2) - math.Sin(radianLat1)*math.Cos(radianLat2)*math.Cos(radianDifLng)
+	return math.Mod(math.Atan2(y, x)*180/math.Pi+360, 360)
+}
+
+// interpolate returns the point at the fraction of the great circle route from p1 to p2.
+// δ = d / R, a = sin((1−f) * δ) / sin(δ), b = sin(f * δ) / sin(δ)
+// x = a * cos(lat₁) * cos(lng₁) + b * cos(lat₂) * cos(lng₂)
+// y = a * cos(lat₁) * sin(lng₁) + b * cos(lat₂) * sin(lng₂)
+// z = a * sin(lat₁) + b * sin(lat₂)
+// lat = atan2(z, √(x² + y²)), lng = atan2(y, x)
+func interpolate(p1, p2 *Point, fraction float64) *Point {
+	if p1 == nil || p2 == nil {
+		return nil
+	}
+
+	delta := haversine(p1, p2) / earthRadius
+	if delta == 0 {
+		return NewPoint(p1.Lng, p1.Lat, nil)
+	}
+
+	radianLat1, radianLng1 := p1.Lat*(math.Pi/180), p1.Lng*(math.Pi/180)
+	radianLat2, radianLng2 := p2.Lat*(math.Pi/180), p2.Lng*(math.Pi/180)
+	a := math.Sin((1-fraction)*delta) / math.Sin(delta)
+	b := math.Sin(fraction*delta) / math.Sin(delta)
+	x := a*math.Cos(radianLat1)*math.Cos(radianLng1) + b*math.Cos(radianLat2)*math.Cos(radianLng2)
+	y := a*math.Cos(radianLat1)*math.Sin(radianLng1) + b*math.Cos(radianLat2)*math.Sin(radianLng2)
+	z := a*math.Sin(radianLat1) + b*math.Sin(radianLat2)
+
+	return NewPoint(math.Atan2(y, x)*180/math.Pi, math.Atan2(z, math.Sqrt(x*x+y*y))*180/math.Pi, nil)
+}
+
+// destination returns the point distance meters away from p along the great circle of the initial bearing.
+// δ = d / R
+// lat₂ = asin(sin(lat₁) * cos(δ) + cos(lat₁) * sin(δ) * cos(θ))
+// lng₂ = lng₁ + atan2(sin(θ) * sin(δ) * cos(lat₁), cos(δ) − sin(lat₁) * sin(lat₂))
+func destination(p *Point, distance, bearing float64) *Point {
+	delta := distance / earthRadius
+	theta := bearing * (math.Pi / 180)
+	radianLat1, radianLng1 := p.Lat*(math.Pi/180), p.Lng*(math.Pi/180)
+
+	radianLat2 := math.Asin(math.Sin(radianLat1)*math.Cos(delta) + math.Cos(radianLat1)*math.Sin(delta)*math.Cos(theta))
+	radianLng2 := radianLng1 + math.Atan2(math.Sin(theta)*math.Sin(delta)*math.Cos(radianLat1), math.Cos(delta)-math.Sin(radianLat1)*math.Sin(radianLat2))
+
+	return NewPoint(WrapLng(radianLng2*180/math.Pi), radianLat2*180/math.Pi, nil)
+}
@@ -1,6 +1,7 @@
 package geohash
 
 import (
+	"math"
 	"reflect"
 	"testing"
 )
@@ -382,56 +383,6 @@ func TestPoint_key(t *testing.T) {
 	}
 }
 
-func TestPoint_circumscribedSquarePointsByCircle(t *testing.T) {
-	type fields struct {
-		Lng float64
-		Lat float64
-		Val any
-	}
-	type args struct {
-		radius uint32
-	}
-	tests := []struct {
-		name   string
-		fields fields
-		args   args
-		want   [9]*Point
-	}{
-		{
-			name: "TestPoint_circumscribedSquarePointsByCircle 1",
-			fields: fields{
-				Lng: 0,
-				Lat: 0,
-				Val: nil,
-			},
-			args: args{radius: 111},
-			want: [9]*Point{
-				&Point{-0.001, 0.001, nil},
-				&Point{0, 0.001, nil},
-				&Point{0.001, 0.001, nil},
-				&Point{-0.001, 0, nil},
-				&Point{0, 0, nil},
-				&Point{0.001, 0, nil},
-				&Point{-0.001, -0.001, nil},
-				&Point{0, -0.001, nil},
-				&Point{0.001, -0.001, nil},
-			},
-		},
-	}
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			p := &Point{
-				Lng: tt.fields.Lng,
-				Lat: tt.fields.Lat,
-				Val: tt.fields.Val,
-			}
-			if got := p.circumscribedSquarePointsByCircle(tt.args.radius); !reflect.DeepEqual(got, tt.want) {
-				t.Errorf("circumscribedSquarePointsByCircle() = %v, want %v", got, tt.want)
-			}
-		})
-	}
-}
-
 func Test_encode(t *testing.T) {
 	type args struct {
 		coordinate float64
@@ -536,59 +487,286 @@ func Test_decode(t *testing.T) {
 	}
 }
 
-func Test_getGeohashLenByDiameter(t *testing.T) {
-	type args struct {
-		diameter uint32
+func TestGeohash_Bounds(t *testing.T) {
+	tests := []struct {
+		name   string
+		g      Geohash
+		want   Rect
+		wantOk bool
+	}{
+		{
+			name:   "TestGeohash_Bounds 1",
+			g:      "S",
+			want:   Rect{MinLng: 0, MinLat: 0, MaxLng: 45, MaxLat: 45},
+			wantOk: true,
+		},
+		{
+			name:   "TestGeohash_Bounds 2",
+			g:      "7Z",
+			want:   Rect{MinLng: -11.25, MinLat: -5.625, MaxLng: 0, MaxLat: 0},
+			wantOk: true,
+		},
+		{
+			name:   "TestGeohash_Bounds 3",
+			g:      "A",
+			wantOk: false,
+		},
 	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := tt.g.Bounds()
+			if ok != tt.wantOk || got != tt.want {
+				t.Errorf("Bounds() = %v, %v, want %v, %v", got, ok, tt.want, tt.wantOk)
+			}
+		})
+	}
+	t.Run("TestGeohash_Bounds contains", func(t *testing.T) {
+		p := NewPoint(121.506377, 31.245105, "东方明珠")
+		if got, _ := p.Geohash().Bounds(); !got.Contains(p) {
+			t.Errorf("Bounds() = %v, want to contain %v", got, p)
+		}
+	})
+}
+
+func Test_bearing(t *testing.T) {
 	tests := []struct {
-		name    string
-		args    args
-		want    uint8
-		wantErr bool
+		name string
+		p1   *Point
+		p2   *Point
+		want float64
 	}{
 		{
-			name: "Test_getGeohashLenByDiameter 1",
-			args: args{
-				diameter: 10,
-			},
-			want:    8,
-			wantErr: false,
+			name: "Test_bearing 1",
+			p1:   NewPoint(0, 0, nil),
+			p2:   NewPoint(0, 1, nil),
+			want: 0,
 		},
 		{
-			name: "Test_getGeohashLenByDiameter 2",
-			args: args{
-				diameter: 100,
-			},
-			want:    7,
-			wantErr: false,
+			name: "Test_bearing 2",
+			p1:   NewPoint(0, 0, nil),
+			p2:   NewPoint(1, 0, nil),
+			want: 90,
 		},
 		{
-			name: "Test_getGeohashLenByDiameter 3",
-			args: args{
-				diameter: 10000,
-			},
-			want:    4,
-			wantErr: false,
+			name: "Test_bearing 3",
+			p1:   NewPoint(0, 0, nil),
+			p2:   NewPoint(-1, 0, nil),
+			want: 270,
 		},
 		{
-			name: "Test_getGeohashLenByDiameter 4",
-			args: args{
-				diameter: 10000000,
-			},
-			want:    0,
-			wantErr: true,
+			name: "Test_bearing 4",
+			p1:   NewPoint(-0.1246, 51.5007, nil),
+			p2:   NewPoint(-74.0445, 40.6892, nil),
+			want: 288.3369,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := bearing(tt.p1, tt.p2); math.Abs(got-tt.want) > 1e-3 {
+				t.Errorf("bearing() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_interpolate(t *testing.T) {
+	tests := []struct {
+		name     string
+		p1       *Point
+		p2       *Point
+		fraction float64
+		want     *Point
+	}{
+		{
+			name:     "Test_interpolate 1",
+			p1:       NewPoint(0, 0, nil),
+			p2:       NewPoint(90, 0, nil),
+			fraction: 0.5,
+			want:     NewPoint(45, 0, nil),
+		},
+		{
+			name:     "Test_interpolate 2",
+			p1:       NewPoint(0, 0, nil),
+			p2:       NewPoint(0, 60, nil),
+			fraction: 0.25,
+			want:     NewPoint(0, 15, nil),
+		},
+		{
+			name:     "Test_interpolate 3",
+			p1:       NewPoint(121.506377, 31.245105, nil),
+			p2:       NewPoint(121.506377, 31.245105, nil),
+			fraction: 0.5,
+			want:     NewPoint(121.506377, 31.245105, nil),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := interpolate(tt.p1, tt.p2, tt.fraction)
+			if math.Abs(got.Lng-tt.want.Lng) > 1e-9 || math.Abs(got.Lat-tt.want.Lat) > 1e-9 {
+				t.Errorf("interpolate() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGeohash_Neighbor(t *testing.T) {
+	tests := []struct {
+		name      string
+		g         Geohash
+		direction Direction
+		want      Geohash
+		wantOk    bool
+	}{
+		{
+			name:      "TestGeohash_Neighbor 1",
+			g:         "S",
+			direction: North,
+			want:      "U",
+			wantOk:    true,
+		},
+		{
+			name:      "TestGeohash_Neighbor 2",
+			g:         "S",
+			direction: SouthWest,
+			want:      "7",
+			wantOk:    true,
+		},
+		{
+			name:      "TestGeohash_Neighbor 3",
+			g:         "X",
+			direction: East,
+			want:      "8",
+			wantOk:    true,
+		},
+		{
+			name:      "TestGeohash_Neighbor 4",
+			g:         "Z",
+			direction: North,
+			wantOk:    false,
+		},
+		{
+			name:      "TestGeohash_Neighbor 5",
+			g:         "WTW3SZYP",
+			direction: East,
+			want:      "WTW3SZYR",
+			wantOk:    true,
+		},
+		{
+			name:      "TestGeohash_Neighbor 6",
+			g:         "A",
+			direction: East,
+			wantOk:    false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := tt.g.Neighbor(tt.direction)
+			if got != tt.want || ok != tt.wantOk {
+				t.Errorf("Neighbor() = %v, %v, want %v, %v", got, ok, tt.want, tt.wantOk)
+			}
+		})
+	}
+}
+
+func TestGeohash_Neighbors(t *testing.T) {
+	t.Run("TestGeohash_Neighbors", func(t *testing.T) {
+		want := [directionCount]Geohash{"U", "V", "T", "M", "K", "7", "E", "G"}
+		if got := Geohash("S").Neighbors(); got != want {
+			t.Errorf("Neighbors() = %v, want %v", got, want)
+		}
+	})
+}
+
+func TestPoint_Destination(t *testing.T) {
+	tests := []struct {
+		name     string
+		p        *Point
+		distance Distance
+		bearing  float64
+		want     *Point
+	}{
+		{
+			name:     "TestPoint_Destination 1",
+			p:        NewPoint(0, 0, nil),
+			distance: metersPerDegree,
+			bearing:  90,
+			want:     NewPoint(1, 0, nil),
+		},
+		{
+			name:     "TestPoint_Destination 2",
+			p:        NewPoint(179.5, 0, nil),
+			distance: metersPerDegree,
+			bearing:  90,
+			want:     NewPoint(-179.5, 0, nil),
+		},
+		{
+			name:     "TestPoint_Destination 3",
+			p:        NewPoint(121.506377, 31.245105, "东方明珠"),
+			distance: NewPoint(121.506377, 31.245105, "东方明珠").DistanceTo(NewPoint(121.4737, 31.2304, "人民广场")),
+			bearing:  NewPoint(121.506377, 31.245105, "东方明珠").Bearing(NewPoint(121.4737, 31.2304, "人民广场")),
+			want:     NewPoint(121.4737, 31.2304, "人民广场"),
+		},
+		{
+			name:     "TestPoint_Destination 4",
+			p:        nil,
+			distance: Kilometer,
+			want:     nil,
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got, err := getGeohashLenByDiameter(tt.args.diameter)
-			if (err != nil) != tt.wantErr {
-				t.Errorf("getGeohashLenByDiameter() error = %v, wantErr %v", err, tt.wantErr)
-				return
+			got := tt.p.Destination(tt.distance, tt.bearing)
+			if (got == nil) != (tt.want == nil) || got != nil && (math.Abs(got.Lng-tt.want.Lng) > 1e-9 || math.Abs(got.Lat-tt.want.Lat) > 1e-9) {
+				t.Errorf("Destination() = %v, want %v", got, tt.want)
 			}
-			if got != tt.want {
-				t.Errorf("getGeohashLenByDiameter() got = %v, want %v", got, tt.want)
+		})
+	}
+}
+
+func TestPoint_Midpoint(t *testing.T) {
+	tests := []struct {
+		name      string
+		p, target *Point
+		want      *Point
+	}{
+		{
+			name:   "TestPoint_Midpoint 1",
+			p:      NewPoint(0, 0, nil),
+			target: NewPoint(10, 0, nil),
+			want:   NewPoint(5, 0, nil),
+		},
+		{
+			name:   "TestPoint_Midpoint 2",
+			p:      NewPoint(179, 10, nil),
+			target: NewPoint(-179, 10, nil),
+			want:   NewPoint(180, 10.001493, nil),
+		},
+		{
+			name:   "TestPoint_Midpoint 3",
+			p:      NewPoint(0, 0, nil),
+			target: nil,
+			want:   nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.p.Midpoint(tt.target)
+			if (got == nil) != (tt.want == nil) || got != nil && (math.Abs(got.Lng-tt.want.Lng) > 1e-6 || math.Abs(got.Lat-tt.want.Lat) > 1e-6) {
+				t.Errorf("Midpoint() = %v, want %v", got, tt.want)
 			}
 		})
 	}
 }
+
+func TestPoint_Interpolate(t *testing.T) {
+	p, target := NewPoint(121.506377, 31.245105, "东方明珠"), NewPoint(121.4737, 31.2304, "人民广场")
+	t.Run("TestPoint_Interpolate", func(t *testing.T) {
+		got := p.Interpolate(target, 0.25)
+		if d := p.DistanceTo(got) - p.DistanceTo(target)/4; math.Abs(d.Meters()) > 1e-6 {
+			t.Errorf("Interpolate() = %v, %v from p, want %v", got, p.DistanceTo(got), p.DistanceTo(target)/4)
+		}
+		if b := p.Bearing(got) - p.Bearing(target); math.Abs(b) > 1e-6 {
+			t.Errorf("Bearing() = %v, want %v", p.Bearing(got), p.Bearing(target))
+		}
+	})
+}
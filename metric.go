@@ -0,0 +1,97 @@
+package geohash
+
+import "math"
+
+type (
+	// Metric measures the distances of the Trie queries, see WithMetric.
+	// DistanceModel measures on the earth, Euclidean and Manhattan on a plane.
+	Metric interface {
+		// Distance returns the distance between p1 and p2.
+		Distance(p1, p2 *Point) Distance
+		// Bound returns the rectangle enclosing the points within radius of center,
+		// the Trie prunes the cells outside it.
+		Bound(center *Point, radius Distance) Rect
+	}
+
+	// Euclidean measures the straight line distance of planar coordinates,
+	// such as a local grid projected into the longitude and latitude of the points.
+	// Scale is the meters per unit of the coordinates, metersPerDegree if zero.
+	// The coordinates are still validated as longitudes and latitudes, so map the grid into [-180, 180] and [-90, 90],
+	// like PlanarPoint does for meters with a zero Scale.
+	Euclidean struct {
+		Scale float64
+	}
+
+	// Manhattan measures the distance along the axes of planar coordinates, such as the blocks of a grid city.
+	// Scale is the meters per unit of the coordinates, metersPerDegree if zero, see Euclidean for the range of the coordinates.
+	Manhattan struct {
+		Scale float64
+	}
+)
+
+// WithMetric sets the Metric used by the Trie queries to filter the points, Spherical by default.
+func WithMetric(metric Metric) TrieOption {
+	return func(t *Trie) {
+		t.metric = metric
+	}
+}
+
+// PlanarPoint creates a Point of the planar coordinates x and y in meters for a Euclidean or Manhattan of a zero Scale,
+// it maps them into degrees, so x must be within about ±20000 km and y within ±10000 km.
+func PlanarPoint(x, y float64, val any) *Point {
+	return NewPoint(x/metersPerDegree, y/metersPerDegree, val)
+}
+
+// Planar returns the planar coordinates in meters of a Point created by PlanarPoint.
+func (p *Point) Planar() (x, y float64) {
+	if p == nil {
+		return 0, 0
+	}
+	return p.Lng * metersPerDegree, p.Lat * metersPerDegree
+}
+
+func (e Euclidean) Distance(p1, p2 *Point) Distance {
+	if p1 == nil || p2 == nil {
+		return 0
+	}
+	return Distance(math.Hypot(p2.Lng-p1.Lng, p2.Lat-p1.Lat) * planarScale(e.Scale))
+}
+
+func (e Euclidean) Bound(center *Point, radius Distance) Rect {
+	return planarRect(center, radius.Meters()/planarScale(e.Scale))
+}
+
+func (m Manhattan) Distance(p1, p2 *Point) Distance {
+	if p1 == nil || p2 == nil {
+		return 0
+	}
+	return Distance((math.Abs(p2.Lng-p1.Lng) + math.Abs(p2.Lat-p1.Lat)) * planarScale(m.Scale))
+}
+
+func (m Manhattan) Bound(center *Point, radius Distance) Rect {
+	return planarRect(center, radius.Meters()/planarScale(m.Scale))
+}
+
+func (t *Trie) distanceMetric() Metric {
+	if t.metric == nil {
+		return Spherical
+	}
+	return t.metric
+}
+
+func planarScale(scale float64) float64 {
+	if scale == 0 {
+		return metersPerDegree
+	}
+	return scale
+}
+
+// planarRect returns the square of half side units around center
+func planarRect(center *Point, units float64) Rect {
+	return Rect{
+		MinLng: math.Max(center.Lng-units, minLng),
+		MinLat: math.Max(center.Lat-units, minLat),
+		MaxLng: math.Min(center.Lng+units, maxLng),
+		MaxLat: math.Min(center.Lat+units, maxLat),
+	}
+}
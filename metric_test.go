@@ -0,0 +1,118 @@
+package geohash
+
+import (
+	"math"
+	"testing"
+)
+
+func TestMetric_Distance(t *testing.T) {
+	tests := []struct {
+		name   string
+		metric Metric
+		p1, p2 *Point
+		want   Distance
+	}{
+		{
+			name:   "TestMetric_Distance 1",
+			metric: Euclidean{Scale: 1},
+			p1:     NewPoint(10, 10, nil),
+			p2:     NewPoint(13, 14, nil),
+			want:   5,
+		},
+		{
+			name:   "TestMetric_Distance 2",
+			metric: Manhattan{Scale: 1},
+			p1:     NewPoint(10, 10, nil),
+			p2:     NewPoint(13, 14, nil),
+			want:   7,
+		},
+		{
+			name:   "TestMetric_Distance 3",
+			metric: Euclidean{},
+			p1:     NewPoint(0, 0, nil),
+			p2:     NewPoint(0, 1, nil),
+			want:   metersPerDegree,
+		},
+		{
+			name:   "TestMetric_Distance 4",
+			metric: Manhattan{Scale: 0.5},
+			p1:     NewPoint(10, 10, nil),
+			p2:     nil,
+			want:   0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.metric.Distance(tt.p1, tt.p2); math.Abs((got - tt.want).Meters()) > 1e-9 {
+				t.Errorf("Distance() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWithMetric(t1 *testing.T) {
+	// planar coordinates in meters
+	origin, a, b, c := NewPoint(0, 0, "origin"), NewPoint(3, 4, "a"), NewPoint(5, 5, "b"), NewPoint(0, 6, "c")
+	tests := []struct {
+		name   string
+		metric Metric
+		radius uint32
+		want   map[*Point]struct{}
+	}{
+		{
+			name:   "TestWithMetric 1",
+			metric: Euclidean{Scale: 1},
+			radius: 5,
+			want:   map[*Point]struct{}{origin: {}, a: {}},
+		},
+		{
+			name:   "TestWithMetric 2",
+			metric: Manhattan{Scale: 1},
+			radius: 7,
+			want:   map[*Point]struct{}{origin: {}, a: {}, c: {}},
+		},
+		{
+			name:   "TestWithMetric 3",
+			metric: Euclidean{Scale: 1},
+			radius: 100,
+			want:   map[*Point]struct{}{origin: {}, a: {}, b: {}, c: {}},
+		},
+	}
+	for _, tt := range tests {
+		t := NewTrie(WithMetric(tt.metric))
+		for _, p := range []*Point{origin, a, b, c} {
+			t.Put(p)
+		}
+		t1.Run(tt.name, func(t1 *testing.T) {
+			got, err := t.GetPointsByCircle(origin, tt.radius)
+			if err != nil {
+				t1.Fatalf("GetPointsByCircle() error = %v", err)
+			}
+			assertSamePoints(t1, "GetPointsByCircle()", got, tt.want)
+			if count, _ := t.CountInCircle(origin, Distance(tt.radius)); count != uint32(len(tt.want)) {
+				t1.Errorf("CountInCircle() = %v, want %v", count, len(tt.want))
+			}
+		})
+	}
+}
+
+func TestPlanarPoint(t1 *testing.T) {
+	// a warehouse of 2 km by 1 km in local meters
+	t := NewTrie(WithMetric(Euclidean{}))
+	dock, shelf, gate := PlanarPoint(0, 0, "dock"), PlanarPoint(1500, 800, "shelf"), PlanarPoint(2000, 1000, "gate")
+	for _, p := range []*Point{dock, shelf, gate} {
+		if err := t.Put(p); err != nil {
+			t1.Fatalf("Put() error = %v", err)
+		}
+	}
+	t1.Run("TestPlanarPoint", func(t1 *testing.T) {
+		got, err := t.GetPointsByCircle(dock, 1700)
+		if err != nil {
+			t1.Fatalf("GetPointsByCircle() error = %v", err)
+		}
+		assertSamePoints(t1, "GetPointsByCircle()", got, map[*Point]struct{}{dock: {}, shelf: {}})
+		if x, y := shelf.Planar(); math.Abs(x-1500) > 1e-9 || math.Abs(y-800) > 1e-9 {
+			t1.Errorf("Planar() = %v, %v, want %v, %v", x, y, 1500, 800)
+		}
+	})
+}
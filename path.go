@@ -0,0 +1,110 @@
+package geohash
+
+import (
+	"fmt"
+	"math"
+)
+
+// CellsAlongPath returns the geohash cells of precision traversed by the great circle segments of the path, in order.
+// A cell is repeated only if the path leaves it and comes back.
+// It returns an ErrInvalidPoint for an empty path or an invalid point, and an ErrInvalidGeohash for an invalid precision.
+func CellsAlongPath(path []*Point, precision int) ([]Geohash, error) {
+	if len(path) == 0 {
+		return nil, fmt.Errorf("%w: empty path", ErrInvalidPoint)
+	}
+	if precision < 1 || precision > geohashLen {
+		return nil, fmt.Errorf("%w: precision %d", ErrInvalidGeohash, precision)
+	}
+	for _, p := range path {
+		if err := p.Validate(); err != nil {
+			return nil, err
+		}
+	}
+
+	lngBits, latBits := cellBits(precision)
+	width := float64(maxLng-minLng) / float64(uint32(1)<<lngBits)
+	height := float64(maxLat-minLat) / float64(uint32(1)<<latBits)
+	// the great circle is split into pieces shorter than a cell, which are walked as straight lines
+	pieceLen := math.Min(width, height) * metersPerDegree
+
+	res := []Geohash{path[0].Geohash()[:precision]}
+	for i := 1; i < len(path); i++ {
+		pieces := int(math.Ceil(haversine(path[i-1], path[i]) / pieceLen))
+		from := path[i-1]
+		for j := 1; j <= pieces; j++ {
+			to := path[i]
+			if j < pieces {
+				to = interpolate(path[i-1], path[i], float64(j)/float64(pieces))
+			}
+			res = walkCells(res, from, to, width, height)
+			from = to
+		}
+	}
+	return res, nil
+}
+
+// walkCells appends the cells crossed by the straight line in longitude/latitude space from the last cell of cells,
+// which contains from, to the cell containing to, stepping from neighbor to neighbor.
+func walkCells(cells []Geohash, from, to *Point, width, height float64) []Geohash {
+	cell := cells[len(cells)-1]
+	precision := len(cell)
+	target := to.Geohash()[:precision]
+
+	// go the short way around the antimeridian
+	difLng := math.Mod(to.Lng-from.Lng+540, 360) - 180
+	difLat := to.Lat - from.Lat
+	bounds, _ := cell.Bounds()
+
+	// the fraction of the line at which it crosses the next cell boundary, and between two boundaries
+	next := func(start, min, max, dif, size float64) (float64, float64, int64) {
+		switch {
+		case dif > 0:
+			return (max - start) / dif, size / dif, 1
+		case dif < 0:
+			return (min - start) / dif, -size / dif, -1
+		default:
+			return math.Inf(1), math.Inf(1), 0
+		}
+	}
+	tLng, stepLng, dirLng := next(from.Lng, bounds.MinLng, bounds.MaxLng, difLng, width)
+	tLat, stepLat, dirLat := next(from.Lat, bounds.MinLat, bounds.MaxLat, difLat, height)
+
+	for cell != target && math.Min(tLng, tLat) <= 1 {
+		var moveLng, moveLat int64
+		switch {
+		case tLng < tLat:
+			moveLng = dirLng
+			tLng += stepLng
+		case tLat < tLng:
+			moveLat = dirLat
+			tLat += stepLat
+		default:
+			// through a corner
+			moveLng, moveLat = dirLng, dirLat
+			tLng += stepLng
+			tLat += stepLat
+		}
+
+		neighbor, ok := cell.Neighbor(directionOf(moveLng, moveLat))
+		if !ok {
+			break
+		}
+		cell = neighbor
+		cells = append(cells, cell)
+	}
+
+	if cell != target {
+		cells = append(cells, target)
+	}
+	return cells
+}
+
+// directionOf returns the Direction of the steps of the longitude and latitude indexes
+func directionOf(difLng, difLat int64) Direction {
+	for d := North; d < directionCount; d++ {
+		if lng, lat := d.offset(); lng == difLng && lat == difLat {
+			return d
+		}
+	}
+	return directionCount
+}
@@ -0,0 +1,104 @@
+package geohash
+
+import (
+	"errors"
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestCellsAlongPath(t *testing.T) {
+	tests := []struct {
+		name      string
+		path      []*Point
+		precision int
+		want      []Geohash
+		wantErr   error
+	}{
+		{
+			name:      "TestCellsAlongPath 1",
+			path:      []*Point{NewPoint(1, 1, nil)},
+			precision: 1,
+			want:      []Geohash{"S"},
+		},
+		{
+			name:      "TestCellsAlongPath 2",
+			path:      []*Point{NewPoint(1, 1, nil), NewPoint(100, 1, nil)},
+			precision: 1,
+			want:      []Geohash{"S", "T", "W"},
+		},
+		{
+			name:      "TestCellsAlongPath 3",
+			path:      []*Point{NewPoint(-1, -1, nil), NewPoint(1, 1, nil)},
+			precision: 1,
+			want:      []Geohash{"7", "S"},
+		},
+		{
+			name:      "TestCellsAlongPath 4",
+			path:      []*Point{NewPoint(179, 1, nil), NewPoint(-179, 1, nil)},
+			precision: 1,
+			want:      []Geohash{"X", "8"},
+		},
+		{
+			name:      "TestCellsAlongPath 5",
+			path:      []*Point{NewPoint(1, 1, nil), nil},
+			precision: 1,
+			wantErr:   ErrInvalidPoint,
+		},
+		{
+			name:      "TestCellsAlongPath 6",
+			path:      []*Point{NewPoint(1, 1, nil)},
+			precision: 9,
+			wantErr:   ErrInvalidGeohash,
+		},
+		{
+			name:      "TestCellsAlongPath 7",
+			path:      []*Point{NewPoint(math.Inf(1), 1, nil)},
+			precision: 1,
+			wantErr:   ErrInvalidPoint,
+		},
+		{
+			name:      "TestCellsAlongPath 8",
+			path:      nil,
+			precision: 1,
+			wantErr:   ErrInvalidPoint,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := CellsAlongPath(tt.path, tt.precision)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("CellsAlongPath() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("CellsAlongPath() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCellsAlongPath_adjacent(t *testing.T) {
+	path := []*Point{
+		NewPoint(121.506377, 31.245105, "东方明珠"),
+		NewPoint(121.4871639, 31.2388556, "上海和平饭店"),
+		NewPoint(121.4737, 31.2304, "人民广场"),
+	}
+	got, err := CellsAlongPath(path, 7)
+	if err != nil {
+		t.Fatalf("CellsAlongPath() error = %v", err)
+	}
+	t.Run("TestCellsAlongPath_adjacent", func(t *testing.T) {
+		if got[0] != path[0].Geohash()[:7] || got[len(got)-1] != path[2].Geohash()[:7] {
+			t.Errorf("CellsAlongPath() = %v, want from %v to %v", got, path[0].Geohash()[:7], path[2].Geohash()[:7])
+		}
+		for i := 1; i < len(got); i++ {
+			adjacent := false
+			for _, neighbor := range got[i-1].Neighbors() {
+				adjacent = adjacent || neighbor == got[i]
+			}
+			if !adjacent {
+				t.Errorf("CellsAlongPath() %v and %v are not adjacent", got[i-1], got[i])
+			}
+		}
+	})
+}
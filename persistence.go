@@ -0,0 +1,329 @@
+package geohash
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"hash"
+	"hash/crc32"
+	"io"
+	"math"
+	"sort"
+)
+
+// The binary format of a Trie:
+//
+//	magic      [4]byte "GHTR"
+//	version    uint8
+//	boxCount   uvarint
+//	boxes      boxCount * (geohash [geohashLen]byte, pointCount uvarint, points)
+//	point      lng float64, lat float64, payloadLen uvarint, payload []byte, deadline uvarint
+//	checksum   uint32 CRC-32 (IEEE) of all preceding bytes
+//
+// Numbers are little endian, a nil payload is stored with payloadLen 0,
+// deadline is the unix nano expiry of a point put with a TTL and 0 otherwise, it is absent in version 1.
+const (
+	formatMagic   = "GHTR"
+	formatVersion = 2
+)
+
+var (
+	ErrCorruptData        = errors.New("corrupt data")
+	ErrUnsupportedVersion = errors.New("unsupported version")
+)
+
+// PayloadCodec converts Point.Val to and from bytes when a Trie is persisted.
+type PayloadCodec interface {
+	Encode(val any) ([]byte, error)
+	Decode(data []byte) (any, error)
+}
+
+// GobCodec is the default PayloadCodec, custom payload types must be registered by gob.Register.
+type GobCodec struct{}
+
+func (GobCodec) Encode(val any) ([]byte, error) {
+	buf := bytes.Buffer{}
+	if err := gob.NewEncoder(&buf).Encode(&val); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (GobCodec) Decode(data []byte) (any, error) {
+	var val any
+	if err := gob.NewDecoder(bytes.NewReader(data)).Decode(&val); err != nil {
+		return nil, err
+	}
+	return val, nil
+}
+
+// WriteTo writes all boxes of the Trie to w, it implements io.WriterTo.
+func (t *Trie) WriteTo(w io.Writer) (int64, error) {
+	if t == nil || t.root == nil {
+		return 0, ErrNilTrie
+	}
+
+	t.RLock()
+	defer t.RUnlock()
+
+	cw := &countWriter{w: w}
+	bw := bufio.NewWriter(cw)
+	checksum := crc32.NewIEEE()
+	fw := &formatWriter{w: io.MultiWriter(bw, checksum), codec: t.payloadCodec()}
+
+	fw.writeBytes([]byte(formatMagic))
+	fw.writeBytes([]byte{formatVersion})
+	boxes := t.root.dfs()
+	fw.writeUvarint(uint64(len(boxes)))
+	for _, box := range boxes {
+		fw.writeBox(box)
+	}
+	if fw.err != nil {
+		return cw.n, fw.err
+	}
+
+	if err := binary.Write(bw, binary.LittleEndian, checksum.Sum32()); err != nil {
+		return cw.n, err
+	}
+	err := bw.Flush()
+	return cw.n, err
+}
+
+// ReadFrom replaces the content of the Trie with the data written by WriteTo, it implements io.ReaderFrom.
+// The Trie is left untouched if the data is corrupt.
+func (t *Trie) ReadFrom(r io.Reader) (int64, error) {
+	if t == nil || t.root == nil {
+		return 0, ErrNilTrie
+	}
+
+	cr := &countReader{r: bufio.NewReader(r)}
+	checksum := crc32.NewIEEE()
+	fr := &formatReader{r: cr, checksum: checksum, codec: t.payloadCodec(), datum: t.datum}
+
+	if magic := fr.readBytes(uint64(len(formatMagic))); fr.err == nil && string(magic) != formatMagic {
+		return cr.n, ErrCorruptData
+	}
+	version := fr.readBytes(1)
+	if fr.err == nil && (version[0] == 0 || version[0] > formatVersion) {
+		return cr.n, fmt.Errorf("%w: %d", ErrUnsupportedVersion, version[0])
+	}
+
+	loaded := &Trie{root: &node{}}
+	boxCount := fr.readUvarint()
+	for i := uint64(0); i < boxCount && fr.err == nil; i++ {
+		fr.readBox(version[0], loaded.putWithDeadline)
+	}
+	if fr.err != nil {
+		return cr.n, fr.err
+	}
+
+	sum := checksum.Sum32()
+	var want uint32
+	if err := binary.Read(cr, binary.LittleEndian, &want); err != nil {
+		return cr.n, fmt.Errorf("%w: %v", ErrCorruptData, err)
+	}
+	if sum != want {
+		return cr.n, fmt.Errorf("%w: checksum mismatch", ErrCorruptData)
+	}
+
+	t.replace(loaded)
+	return cr.n, nil
+}
+
+// replace swaps the points of the Trie for the ones of loaded
+func (t *Trie) replace(loaded *Trie) {
+	t.Lock()
+	defer t.Unlock()
+
+	t.root, t.expiring, t.expiry = loaded.root, loaded.expiring, loaded.expiry
+}
+
+// WithPayloadCodec sets the PayloadCodec used by WriteTo and ReadFrom, GobCodec by default.
+func WithPayloadCodec(codec PayloadCodec) TrieOption {
+	return func(t *Trie) {
+		t.codec = codec
+	}
+}
+
+func (t *Trie) payloadCodec() PayloadCodec {
+	if t.codec == nil {
+		return GobCodec{}
+	}
+	return t.codec
+}
+
+// formatWriter writes the binary format, the first error is kept and the later writes are skipped
+type formatWriter struct {
+	w     io.Writer
+	codec PayloadCodec
+	err   error
+}
+
+func (fw *formatWriter) writeBytes(b []byte) {
+	if fw.err == nil {
+		_, fw.err = fw.w.Write(b)
+	}
+}
+
+func (fw *formatWriter) writeUvarint(v uint64) {
+	buf := make([]byte, binary.MaxVarintLen64)
+	fw.writeBytes(buf[:binary.PutUvarint(buf, v)])
+}
+
+func (fw *formatWriter) writeFloat64(v float64) {
+	buf := make([]byte, 8)
+	binary.LittleEndian.PutUint64(buf, math.Float64bits(v))
+	fw.writeBytes(buf)
+}
+
+func (fw *formatWriter) writeBox(box *Box) {
+	fw.writeBytes([]byte(box.GetGeohash()))
+
+	// sorted by key, so the same Trie is always written to the same bytes
+	// the expired points are written too, they expire again once read
+	pointSet := box.PointSet
+	keys := make([]string, 0, len(pointSet))
+	for key := range pointSet {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	fw.writeUvarint(uint64(len(keys)))
+	for _, key := range keys {
+		fw.writePoint(pointSet[key])
+		fw.writeUvarint(uint64(box.expireAt[key]))
+	}
+}
+
+func (fw *formatWriter) writePoint(point *Point) {
+	fw.writeFloat64(point.GetLng())
+	fw.writeFloat64(point.GetLat())
+
+	var payload []byte
+	if val := point.GetVal(); val != nil && fw.err == nil {
+		payload, fw.err = fw.codec.Encode(val)
+	}
+	fw.writeUvarint(uint64(len(payload)))
+	fw.writeBytes(payload)
+}
+
+// formatReader reads the binary format, the first error is kept and the later reads are skipped
+type formatReader struct {
+	r        io.Reader
+	checksum hash.Hash32
+	codec    PayloadCodec
+	datum    Datum // the datum of the points read
+	err      error
+}
+
+func (fr *formatReader) readBytes(n uint64) []byte {
+	if fr.err != nil {
+		return nil
+	}
+	// the buffer grows with the data actually read, so that a corrupt length cannot exhaust the memory
+	buf := bytes.Buffer{}
+	if _, err := io.CopyN(&buf, fr.r, int64(n)); err != nil {
+		fr.err = fmt.Errorf("%w: %v", ErrCorruptData, err)
+		return nil
+	}
+	if fr.checksum != nil {
+		_, _ = fr.checksum.Write(buf.Bytes())
+	}
+	return buf.Bytes()
+}
+
+func (fr *formatReader) readUvarint() uint64 {
+	if fr.err != nil {
+		return 0
+	}
+	v, err := binary.ReadUvarint(fr)
+	if err != nil {
+		fr.err = fmt.Errorf("%w: %v", ErrCorruptData, err)
+		return 0
+	}
+	return v
+}
+
+// ReadByte implements io.ByteReader for binary.ReadUvarint
+func (fr *formatReader) ReadByte() (byte, error) {
+	b := fr.readBytes(1)
+	if fr.err != nil {
+		return 0, fr.err
+	}
+	return b[0], nil
+}
+
+func (fr *formatReader) readFloat64() float64 {
+	b := fr.readBytes(8)
+	if fr.err != nil {
+		return 0
+	}
+	return math.Float64frombits(binary.LittleEndian.Uint64(b))
+}
+
+// readBox passes the points of the box and their deadlines to put
+func (fr *formatReader) readBox(version byte, put func(point *Point, deadline int64)) {
+	geohash := Geohash(fr.readBytes(geohashLen))
+	if fr.err == nil && !geohash.valid() {
+		fr.err = fmt.Errorf("%w: invalid geohash %q", ErrCorruptData, geohash)
+	}
+
+	pointCount := fr.readUvarint()
+	for i := uint64(0); i < pointCount && fr.err == nil; i++ {
+		point := fr.readPoint()
+		var deadline int64
+		if version >= 2 {
+			deadline = int64(fr.readUvarint())
+		}
+		if fr.err != nil {
+			return
+		}
+		if point.Geohash() != geohash {
+			fr.err = fmt.Errorf("%w: point %v outside box %v", ErrCorruptData, point.key(), geohash)
+			return
+		}
+		put(point, deadline)
+	}
+}
+
+func (fr *formatReader) readPoint() *Point {
+	lng := fr.readFloat64()
+	lat := fr.readFloat64()
+
+	var val any
+	if payloadLen := fr.readUvarint(); payloadLen > 0 && fr.err == nil {
+		payload := fr.readBytes(payloadLen)
+		if fr.err == nil {
+			var err error
+			if val, err = fr.codec.Decode(payload); err != nil {
+				fr.err = err
+			}
+		}
+	}
+	return &Point{Lng: lng, Lat: lat, Val: val, Datum: fr.datum}
+}
+
+type countWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (cw *countWriter) Write(p []byte) (int, error) {
+	n, err := cw.w.Write(p)
+	cw.n += int64(n)
+	return n, err
+}
+
+type countReader struct {
+	r io.Reader
+	n int64
+}
+
+func (cr *countReader) Read(p []byte) (int, error) {
+	n, err := cr.r.Read(p)
+	cr.n += int64(n)
+	return n, err
+}
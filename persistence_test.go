@@ -0,0 +1,202 @@
+package geohash
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+	"math"
+	"reflect"
+	"strconv"
+	"testing"
+	"time"
+)
+
+type intCodec struct{}
+
+func (intCodec) Encode(val any) ([]byte, error) {
+	return []byte(strconv.Itoa(val.(int))), nil
+}
+
+func (intCodec) Decode(data []byte) (any, error) {
+	return strconv.Atoi(string(data))
+}
+
+func TestTrie_WriteTo(t1 *testing.T) {
+	t := NewTrie()
+	p1 := NewPoint(13.361389, 38.115556, "Palermo")
+	p2 := NewPoint(121.506377, 31.245105, "东方明珠")
+	p3 := NewPoint(121.506378, 31.245105, nil)
+	t.Put(p1)
+	t.Put(p2)
+	t.Put(p3)
+
+	buf := bytes.Buffer{}
+	n, err := t.WriteTo(&buf)
+	t1.Run("TestTrie_WriteTo", func(t1 *testing.T) {
+		if err != nil {
+			t1.Fatalf("WriteTo() error = %v, wantErr %v", err, nil)
+		}
+		if n != int64(buf.Len()) {
+			t1.Errorf("WriteTo() = %v, want %v", n, buf.Len())
+		}
+
+		again := bytes.Buffer{}
+		if _, err = t.WriteTo(&again); err != nil || !bytes.Equal(again.Bytes(), buf.Bytes()) {
+			t1.Errorf("WriteTo() is not deterministic, error = %v", err)
+		}
+	})
+	t1.Run("TestTrie_ReadFrom", func(t1 *testing.T) {
+		got := NewTrie()
+		got.Put(NewPoint(0, 0, "stale"))
+		if n, err := got.ReadFrom(bytes.NewReader(buf.Bytes())); err != nil || n != int64(buf.Len()) {
+			t1.Fatalf("ReadFrom() = %v, error = %v", n, err)
+		}
+		if !reflect.DeepEqual(got.root, t.root) {
+			t1.Errorf("ReadFrom() got = %v, want %v", got.root.dfs(), t.root.dfs())
+		}
+	})
+}
+
+func TestTrie_ReadFrom(t1 *testing.T) {
+	t := NewTrie(WithPayloadCodec(intCodec{}))
+	t.Put(NewPoint(13.361389, 38.115556, 1))
+	t.Put(NewPoint(121.506377, 31.245105, 2))
+	buf := bytes.Buffer{}
+	if _, err := t.WriteTo(&buf); err != nil {
+		t1.Fatalf("WriteTo() error = %v", err)
+	}
+	data := buf.Bytes()
+
+	corrupt := func(i int, b byte) []byte {
+		res := append([]byte{}, data...)
+		res[i] = b
+		return res
+	}
+	tests := []struct {
+		name    string
+		data    []byte
+		wantErr error
+	}{
+		{
+			name: "TestTrie_ReadFrom 1",
+			data: data,
+		},
+		{
+			name:    "TestTrie_ReadFrom 2",
+			data:    corrupt(0, 'X'),
+			wantErr: ErrCorruptData,
+		},
+		{
+			name:    "TestTrie_ReadFrom 3",
+			data:    corrupt(4, formatVersion+1),
+			wantErr: ErrUnsupportedVersion,
+		},
+		{
+			name:    "TestTrie_ReadFrom 4",
+			data:    corrupt(len(data)-1, data[len(data)-1]+1),
+			wantErr: ErrCorruptData,
+		},
+		{
+			name:    "TestTrie_ReadFrom 5",
+			data:    data[:len(data)-10],
+			wantErr: ErrCorruptData,
+		},
+	}
+	for _, tt := range tests {
+		t1.Run(tt.name, func(t1 *testing.T) {
+			got := NewTrie(WithPayloadCodec(intCodec{}))
+			got.Put(NewPoint(0, 0, 0))
+			_, err := got.ReadFrom(bytes.NewReader(tt.data))
+			if !errors.Is(err, tt.wantErr) {
+				t1.Fatalf("ReadFrom() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				if _, ok := got.Get(NewPoint(0, 0, nil).Geohash()); !ok {
+					t1.Errorf("ReadFrom() modified the Trie on error")
+				}
+				return
+			}
+			if box, ok := got.Get("WTW3SZYP"); !ok || box.GetAllPoints()[0].GetVal() != 2 {
+				t1.Errorf("ReadFrom() got = %v, want %v", box, 2)
+			}
+		})
+	}
+}
+
+func TestTrie_ReadFrom_deadline(t1 *testing.T) {
+	t := NewTrie()
+	p1 := NewPoint(121.506377, 31.245105, "东方明珠")
+	t.PutWithTTL(p1, time.Hour)
+	buf := bytes.Buffer{}
+	if _, err := t.WriteTo(&buf); err != nil {
+		t1.Fatalf("WriteTo() error = %v", err)
+	}
+	t1.Run("TestTrie_ReadFrom_deadline", func(t1 *testing.T) {
+		got := NewTrie()
+		if _, err := got.ReadFrom(&buf); err != nil {
+			t1.Fatalf("ReadFrom() error = %v", err)
+		}
+		box, _ := got.Get(p1.Geohash())
+		if box.expireAt[p1.key()] != t.root.search(string(p1.Geohash())).expireAt[p1.key()] {
+			t1.Errorf("ReadFrom() deadline = %v", box.expireAt)
+		}
+		if _, ok := got.expiring[p1.Geohash()]; !ok {
+			t1.Errorf("ReadFrom() expiring = %v", got.expiring)
+		}
+	})
+	t1.Run("TestTrie_ReadFrom version 1", func(t1 *testing.T) {
+		data := []byte(formatMagic)
+		data = append(data, 1, 1)
+		data = append(data, p1.Geohash()...)
+		data = append(data, 1)
+		data = binary.LittleEndian.AppendUint64(data, math.Float64bits(p1.Lng))
+		data = binary.LittleEndian.AppendUint64(data, math.Float64bits(p1.Lat))
+		data = append(data, 0)
+		data = binary.LittleEndian.AppendUint32(data, crc32.ChecksumIEEE(data))
+
+		got := NewTrie()
+		if _, err := got.ReadFrom(bytes.NewReader(data)); err != nil {
+			t1.Fatalf("ReadFrom() error = %v", err)
+		}
+		want := NewBox(p1.Geohash(), map[string]*Point{p1.key(): NewPoint(p1.Lng, p1.Lat, nil)})
+		if box, _ := got.Get(p1.Geohash()); !reflect.DeepEqual(box, want) {
+			t1.Errorf("ReadFrom() got = %v, want %v", box, want)
+		}
+	})
+}
+
+func TestGobCodec(t *testing.T) {
+	tests := []struct {
+		name string
+		val  any
+	}{
+		{
+			name: "TestGobCodec 1",
+			val:  "东方明珠",
+		},
+		{
+			name: "TestGobCodec 2",
+			val:  42,
+		},
+		{
+			name: "TestGobCodec 3",
+			val:  []float64{1.5, 2.5},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := GobCodec{}.Encode(tt.val)
+			if err != nil {
+				t.Fatalf("Encode() error = %v", err)
+			}
+			got, err := GobCodec{}.Decode(data)
+			if err != nil {
+				t.Fatalf("Decode() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.val) {
+				t.Errorf("Decode() = %v, want %v", got, tt.val)
+			}
+		})
+	}
+}
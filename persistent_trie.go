@@ -0,0 +1,199 @@
+package geohash
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+type (
+	// PersistentTrie is a copy-on-write Trie.
+	// Writers copy the path from the root to the changed leaf and publish the new root atomically,
+	// so readers never lock and a Snapshot is never affected by later writes.
+	PersistentTrie struct {
+		root atomic.Pointer[node]
+
+		sync.Mutex // serializes writers only
+	}
+
+	// Snapshot is an immutable and lock-free view of a PersistentTrie.
+	Snapshot struct {
+		root *node
+	}
+)
+
+func NewPersistentTrie() *PersistentTrie {
+	t := &PersistentTrie{}
+	t.root.Store(&node{})
+	return t
+}
+
+// Snapshot returns the current state of the PersistentTrie in O(1).
+func (t *PersistentTrie) Snapshot() *Snapshot {
+	if t == nil {
+		return nil
+	}
+	return &Snapshot{root: t.root.Load()}
+}
+
+func (t *PersistentTrie) Get(geohash Geohash) (*Box, bool) {
+	return t.Snapshot().Get(geohash)
+}
+
+func (t *PersistentTrie) GetByPrefix(prefix string) []*Box {
+	return t.Snapshot().GetByPrefix(prefix)
+}
+
+func (t *PersistentTrie) GetPointsByCircle(center *Point, radius uint32) ([]*Point, error) {
+	return t.Snapshot().GetPointsByCircle(center, radius)
+}
+
+func (t *PersistentTrie) Count() uint32 {
+	return t.Snapshot().Count()
+}
+
+func (t *PersistentTrie) CountPoints() uint32 {
+	return t.Snapshot().CountPoints()
+}
+
+func (t *PersistentTrie) CountByPrefix(prefix string) uint32 {
+	return t.Snapshot().CountByPrefix(prefix)
+}
+
+func (t *PersistentTrie) Put(point *Point) error {
+	if t == nil {
+		return ErrNilTrie
+	}
+	if err := point.Validate(); err != nil {
+		return err
+	}
+
+	t.Lock()
+	defer t.Unlock()
+
+	root := t.root.Load()
+	geohash := point.Geohash()
+	leaf := root.search(string(geohash))
+	isNewBox := leaf == nil || !leaf.isLeaf
+	isNewPoint := isNewBox || leaf.PointSet[point.key()] == nil
+	t.root.Store(root.putCopy(point, geohash, 0, isNewBox, isNewPoint))
+	return nil
+}
+
+func (t *PersistentTrie) Delete(geohash Geohash) bool {
+	if t == nil || !geohash.valid() {
+		return false
+	}
+
+	t.Lock()
+	defer t.Unlock()
+
+	root := t.root.Load()
+	leaf := root.search(string(geohash))
+	if leaf == nil || !leaf.isLeaf {
+		return false
+	}
+
+	newRoot := root.deleteCopy(geohash, 0, leaf.pointCount)
+	if newRoot == nil {
+		newRoot = &node{}
+	}
+	t.root.Store(newRoot)
+	return true
+}
+
+func (s *Snapshot) Get(geohash Geohash) (*Box, bool) {
+	if s == nil || s.root == nil || !geohash.valid() {
+		return nil, false
+	}
+	return s.root.get(geohash)
+}
+
+func (s *Snapshot) GetByPrefix(prefix string) []*Box {
+	if s == nil || s.root == nil || len(prefix) == 0 {
+		return nil
+	}
+	return s.root.getByPrefix(prefix)
+}
+
+func (s *Snapshot) GetPointsByCircle(center *Point, radius uint32) ([]*Point, error) {
+	if s == nil || s.root == nil {
+		return nil, ErrNilTrie
+	}
+	if err := center.Validate(); err != nil {
+		return nil, err
+	}
+	if radius == 0 {
+		return nil, ErrInvalidRadius
+	}
+
+	return s.root.collect(newCircleRegion(center, Distance(radius), Spherical)), nil
+}
+
+// Count returns the number of boxes, see CountPoints for the number of points.
+func (s *Snapshot) Count() uint32 {
+	if s == nil || s.root == nil {
+		return 0
+	}
+	return s.root.passCount
+}
+
+func (s *Snapshot) CountPoints() uint32 {
+	if s == nil || s.root == nil {
+		return 0
+	}
+	return s.root.pointCount
+}
+
+func (s *Snapshot) CountByPrefix(prefix string) uint32 {
+	if s == nil || s.root == nil || len(prefix) == 0 {
+		return 0
+	}
+	return s.root.countByPrefix(prefix)
+}
+
+// putCopy returns a copy of the node with point inserted, the node itself is left untouched.
+// isNewBox and isNewPoint report whether the point creates a new leaf or a new key,
+// which change the passCount and the pointCount along the path.
+func (n *node) putCopy(point *Point, geohash Geohash, depth int, isNewBox, isNewPoint bool) *node {
+	c := &node{}
+	if n != nil {
+		*c = *n
+	}
+	if isNewPoint {
+		c.pointCount++
+	}
+
+	if depth == geohashLen {
+		pointSet := make(map[string]*Point, len(c.GetPointSet())+1)
+		for k, v := range c.GetPointSet() {
+			pointSet[k] = v
+		}
+		pointSet[point.key()] = point
+
+		c.isLeaf = true
+		c.Box = NewBox(geohash, pointSet)
+		return c
+	}
+
+	if isNewBox {
+		c.passCount++
+	}
+	index := decode(geohash[depth])
+	c.children[index] = c.children[index].putCopy(point, geohash, depth+1, isNewBox, isNewPoint)
+	return c
+}
+
+// deleteCopy returns a copy of the node without the leaf of geohash holding pointCount points,
+// nil if no Box passes it any more. The leaf must exist.
+func (n *node) deleteCopy(geohash Geohash, depth int, pointCount uint32) *node {
+	if depth == geohashLen || n.passCount <= 1 {
+		return nil
+	}
+
+	c := *n
+	c.passCount--
+	c.pointCount -= pointCount
+	index := decode(geohash[depth])
+	c.children[index] = n.children[index].deleteCopy(geohash, depth+1, pointCount)
+	return &c
+}
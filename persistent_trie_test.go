@@ -0,0 +1,156 @@
+package geohash
+
+import (
+	"reflect"
+	"sync"
+	"testing"
+)
+
+func TestPersistentTrie_Snapshot(t1 *testing.T) {
+	t := NewPersistentTrie()
+	p1 := NewPoint(13.361389, 38.115556, "Palermo")
+	p2 := NewPoint(121.506377, 31.245105, "东方明珠")
+	t.Put(p1)
+	snapshot := t.Snapshot()
+	t.Put(p2)
+	t.Put(NewPoint(13.361389, 38.115556, "Palermo 2"))
+	t.Put(NewPoint(13.361390, 38.115556, "Palermo 3"))
+	t1.Run("TestPersistentTrie_CountByPrefix", func(t1 *testing.T) {
+		if got := t.CountByPrefix(string(p1.Geohash())); got != 2 {
+			t1.Errorf("CountByPrefix() = %v, want %v", got, 2)
+		}
+		if got := snapshot.CountByPrefix(string(p1.Geohash())); got != 1 {
+			t1.Errorf("CountByPrefix() = %v, want %v", got, 1)
+		}
+	})
+	t.Delete(p1.Geohash())
+	t1.Run("TestPersistentTrie_Snapshot", func(t1 *testing.T) {
+		if got := snapshot.Count(); got != 1 {
+			t1.Errorf("Count() = %v, want %v", got, 1)
+		}
+		want := NewBox(p1.Geohash(), map[string]*Point{p1.key(): p1})
+		if got, ok := snapshot.Get(p1.Geohash()); !ok || !reflect.DeepEqual(got, want) {
+			t1.Errorf("Get() = %v, want %v", got, want)
+		}
+		if _, ok := snapshot.Get(p2.Geohash()); ok {
+			t1.Errorf("Get() = %v, want %v", ok, false)
+		}
+	})
+}
+
+func TestPersistentTrie_Get(t1 *testing.T) {
+	t := NewPersistentTrie()
+	p1 := NewPoint(13.361389, 38.115556, "Palermo")
+	p2 := NewPoint(121.506377, 31.245105, "东方明珠")
+	t.Put(p1)
+	t.Put(p2)
+	t1.Run("TestPersistentTrie_Get", func(t1 *testing.T) {
+		_, got := t.Get(NewPoint(121.4871639, 31.2388556, "上海和平饭店").Geohash())
+		if got != false {
+			t1.Errorf("Get() want %v", false)
+		}
+		got1, got2 := t.Get(p1.Geohash())
+		if got2 != true {
+			t1.Errorf("Get() got1 = %v, want %v", got1, true)
+		}
+	})
+}
+
+func TestPersistentTrie_GetByPrefix(t1 *testing.T) {
+	t := NewPersistentTrie()
+	p1 := NewPoint(13.361389, 38.115556, "Palermo")
+	p2 := NewPoint(121.506377, 31.245105, "东方明珠")
+	t.Put(p1)
+	t.Put(p2)
+	t1.Run("TestPersistentTrie_GetByPrefix", func(t1 *testing.T) {
+		want := []*Box{NewBox(p1.Geohash(), map[string]*Point{p1.key(): p1})}
+		if got := t.GetByPrefix(string(p1.Geohash())[:7]); !reflect.DeepEqual(got, want) {
+			t1.Errorf("GetByPrefix() = %v, want %v", got, want)
+		}
+		if got := t.GetByPrefix("?"); got != nil {
+			t1.Errorf("GetByPrefix() = %v, want %v", got, nil)
+		}
+	})
+}
+
+func TestPersistentTrie_Delete(t1 *testing.T) {
+	t := NewPersistentTrie()
+	p1 := NewPoint(121.506377, 31.245105, "东方明珠")
+	p2 := NewPoint(121.506577, 31.245105, "东方明珠东")
+	t.Put(p1)
+	t.Put(p2)
+	t1.Run("TestPersistentTrie_Delete", func(t1 *testing.T) {
+		if got := t.Delete(p1.Geohash()); got != true {
+			t1.Errorf("Delete() = %v, want %v", got, true)
+		}
+		if got := t.Delete(p1.Geohash()); got != false {
+			t1.Errorf("Delete() = %v, want %v", got, false)
+		}
+		if got := t.Count(); got != 1 {
+			t1.Errorf("Count() = %v, want %v", got, 1)
+		}
+		if got := t.CountPoints(); got != 1 {
+			t1.Errorf("CountPoints() = %v, want %v", got, 1)
+		}
+		if got := t.Delete(p2.Geohash()); got != true {
+			t1.Errorf("Delete() = %v, want %v", got, true)
+		}
+		if got := t.Count(); got != 0 {
+			t1.Errorf("Count() = %v, want %v", got, 0)
+		}
+	})
+}
+
+func TestPersistentTrie_GetPointsByCircle(t1 *testing.T) {
+	t := NewPersistentTrie()
+	p1 := NewPoint(13.361389, 38.115556, "Palermo")
+	p2 := NewPoint(121.506377, 31.245105, "东方明珠")
+	t.Put(p1)
+	t.Put(p2)
+	t1.Run("TestPersistentTrie_GetPointsByCircle 1", func(t1 *testing.T) {
+		got, err := t.GetPointsByCircle(NewPoint(121.4871639, 31.2388556, "上海和平饭店"), 10000)
+		if err != nil {
+			t1.Errorf("GetPointsByCircle() error = %v, wantErr %v", err, nil)
+			return
+		}
+		if !reflect.DeepEqual(got, []*Point{p2}) {
+			t1.Errorf("GetPointsByCircle() got = %v, want %v", got, []*Point{p2})
+		}
+	})
+	t1.Run("TestPersistentTrie_GetPointsByCircle 2", func(t1 *testing.T) {
+		got, err := t.GetPointsByCircle(NewPoint(13.9, 38.1, nil), 100000)
+		if err != nil {
+			t1.Errorf("GetPointsByCircle() error = %v, wantErr %v", err, nil)
+			return
+		}
+		if !reflect.DeepEqual(got, []*Point{p1}) {
+			t1.Errorf("GetPointsByCircle() got = %v, want %v", got, []*Point{p1})
+		}
+	})
+}
+
+func TestPersistentTrie_concurrency(t1 *testing.T) {
+	t := NewPersistentTrie()
+	center := NewPoint(121.506377, 31.245105, "东方明珠")
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 1000; i++ {
+			t.Put(NewPoint(center.Lng+float64(i)/1e5, center.Lat, i))
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 1000; i++ {
+			// a snapshot is consistent: the boxes found match its count
+			snapshot := t.Snapshot()
+			if got, want := len(snapshot.GetByPrefix("W")), snapshot.Count(); uint32(got) != want {
+				t1.Errorf("GetByPrefix() = %v, want %v", got, want)
+				return
+			}
+		}
+	}()
+	wg.Wait()
+}
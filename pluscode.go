@@ -0,0 +1,257 @@
+package geohash
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+const (
+	plusCodeAlphabet  = "23456789CFGHJMPQRVWX"
+	plusCodeSeparator = '+'
+	plusCodePadding   = '0'
+	plusCodeBase      = 20
+
+	plusCodeSeparatorPosition = 8
+	plusCodePairLen           = 10 // the digits encoding latitude and longitude in pairs
+	plusCodeGridLen           = 5  // the digits refining a 4 x 5 grid after the pairs
+	plusCodeMaxLen            = plusCodePairLen + plusCodeGridLen
+	plusCodeGridColumns       = 4
+	plusCodeGridRows          = 5
+
+	plusCodePairPrecision     = 8000 // plusCodeBase³, the inverse of the resolution of the last pair
+	plusCodePairFirstValue    = 160000
+	plusCodeGridLatFirstValue = 625 // plusCodeGridRows⁴
+	plusCodeGridLngFirstValue = 256 // plusCodeGridColumns⁴
+	plusCodeFinalLatPrecision = plusCodePairPrecision * 3125
+	plusCodeFinalLngPrecision = plusCodePairPrecision * 1024
+)
+
+// plusCodePairResolutions are the degrees of the digit pairs
+var plusCodePairResolutions = [plusCodePairLen / 2]float64{20, 1, 0.05, 0.0025, 0.000125}
+
+// PlusCodeArea is the rectangle an Open Location Code denotes, Length is the number of its digits.
+type PlusCodeArea struct {
+	Rect
+	Length int
+}
+
+// Geohashes returns the geohash cells of precision intersecting the area, to query a Trie by a Plus Code.
+func (a *PlusCodeArea) Geohashes(precision int) []Geohash {
+	if a == nil || precision < 1 || precision > geohashLen {
+		return nil
+	}
+
+	cells := a.cover(precision)
+	res := make([]Geohash, 0, len(cells))
+	for _, cell := range cells {
+		res = append(res, Geohash(cell))
+	}
+	return res
+}
+
+// PlusCode encodes the point into a full Open Location Code of length digits like "8FVC9G8F+6W",
+// length is 2, 4, 6, 8 or from 10 to 15, 10 gives about 14 meters.
+func (p *Point) PlusCode(length int) (string, error) {
+	if length < 2 || length < plusCodePairLen && length%2 == 1 || length > plusCodeMaxLen {
+		return "", fmt.Errorf("%w: length %d", ErrInvalidPlusCode, length)
+	}
+	if err := p.Validate(); err != nil {
+		return "", err
+	}
+
+	lat, lng := p.Lat, WrapLng(p.Lng)
+	if lat == maxLat {
+		// the north pole is put into the area below it
+		lat -= plusCodeLatPrecision(length)
+	}
+	latVal := int64(math.Round((lat-minLat)*plusCodeFinalLatPrecision*1e6) / 1e6)
+	lngVal := int64(math.Round((lng-minLng)*plusCodeFinalLngPrecision*1e6) / 1e6)
+
+	code := make([]byte, plusCodeMaxLen)
+	if length > plusCodePairLen {
+		for i := plusCodeMaxLen - 1; i >= plusCodePairLen; i-- {
+			code[i] = plusCodeAlphabet[latVal%plusCodeGridRows*plusCodeGridColumns+lngVal%plusCodeGridColumns]
+			latVal /= plusCodeGridRows
+			lngVal /= plusCodeGridColumns
+		}
+	} else {
+		latVal /= plusCodeFinalLatPrecision / plusCodePairPrecision
+		lngVal /= plusCodeFinalLngPrecision / plusCodePairPrecision
+	}
+	for i := plusCodePairLen - 1; i > 0; i -= 2 {
+		code[i] = plusCodeAlphabet[lngVal%plusCodeBase]
+		code[i-1] = plusCodeAlphabet[latVal%plusCodeBase]
+		latVal /= plusCodeBase
+		lngVal /= plusCodeBase
+	}
+
+	if length < plusCodeSeparatorPosition {
+		return string(code[:length]) + strings.Repeat(string(plusCodePadding), plusCodeSeparatorPosition-length) + string(plusCodeSeparator), nil
+	}
+	return string(code[:plusCodeSeparatorPosition]) + string(plusCodeSeparator) + string(code[plusCodeSeparatorPosition:length]), nil
+}
+
+// DecodePlusCode decodes a full Open Location Code into its area, recover a short code with RecoverPlusCode first.
+func DecodePlusCode(code string) (*PlusCodeArea, error) {
+	if !isFullPlusCode(code) {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidPlusCode, code)
+	}
+
+	digits := strings.ToUpper(strings.NewReplacer(string(plusCodeSeparator), "", string(plusCodePadding), "").Replace(code))
+	if len(digits) > plusCodeMaxLen {
+		digits = digits[:plusCodeMaxLen]
+	}
+
+	var latVal, lngVal int64
+	pairValue := int64(plusCodePairFirstValue)
+	pairLen := int(math.Min(float64(len(digits)), plusCodePairLen))
+	for i := 0; i < pairLen; i += 2 {
+		latVal += int64(strings.IndexByte(plusCodeAlphabet, digits[i])) * pairValue
+		lngVal += int64(strings.IndexByte(plusCodeAlphabet, digits[i+1])) * pairValue
+		if i < pairLen-2 {
+			pairValue /= plusCodeBase
+		}
+	}
+	latPrecision := float64(pairValue) / plusCodePairPrecision
+	lngPrecision := latPrecision
+
+	var gridLatVal, gridLngVal int64
+	if len(digits) > plusCodePairLen {
+		rowValue, columnValue := int64(plusCodeGridLatFirstValue), int64(plusCodeGridLngFirstValue)
+		for i := plusCodePairLen; i < len(digits); i++ {
+			digit := int64(strings.IndexByte(plusCodeAlphabet, digits[i]))
+			gridLatVal += digit / plusCodeGridColumns * rowValue
+			gridLngVal += digit % plusCodeGridColumns * columnValue
+			if i < len(digits)-1 {
+				rowValue /= plusCodeGridRows
+				columnValue /= plusCodeGridColumns
+			}
+		}
+		latPrecision = float64(rowValue) / plusCodeFinalLatPrecision
+		lngPrecision = float64(columnValue) / plusCodeFinalLngPrecision
+	}
+
+	lat := float64(latVal)/plusCodePairPrecision + float64(gridLatVal)/plusCodeFinalLatPrecision + minLat
+	lng := float64(lngVal)/plusCodePairPrecision + float64(gridLngVal)/plusCodeFinalLngPrecision + minLng
+	return &PlusCodeArea{
+		Rect:   Rect{MinLng: lng, MinLat: lat, MaxLng: lng + lngPrecision, MaxLat: math.Min(lat+latPrecision, maxLat)},
+		Length: len(digits),
+	}, nil
+}
+
+// ShortenPlusCode removes as many leading digits from the full code as can be recovered from the reference,
+// which should be within a few kilometers of the code, like "8FVC9G8F+6W" into "9G8F+6W" in Zurich.
+func ShortenPlusCode(code string, reference *Point) (string, error) {
+	if !isFullPlusCode(code) || strings.IndexByte(code, plusCodePadding) >= 0 {
+		return "", fmt.Errorf("%w: %q", ErrInvalidPlusCode, code)
+	}
+	if err := reference.Validate(); err != nil {
+		return "", err
+	}
+
+	area, _ := DecodePlusCode(code)
+	center := area.Center()
+	distance := math.Max(math.Abs(center.Lat-reference.Lat), math.Abs(center.Lng-WrapLng(reference.Lng)))
+	code = strings.ToUpper(code)
+	for i := len(plusCodePairResolutions) - 2; i >= 1; i-- {
+		// keep a safety margin to the edge of the area the short code recovers to
+		if distance < plusCodePairResolutions[i]*0.3 {
+			return code[(i+1)*2:], nil
+		}
+	}
+	return code, nil
+}
+
+// RecoverPlusCode recovers the full code of a short code nearest to the reference, a full code is returned as it is.
+func RecoverPlusCode(short string, reference *Point) (string, error) {
+	if isFullPlusCode(short) {
+		return strings.ToUpper(short), nil
+	}
+	separator := strings.IndexByte(short, plusCodeSeparator)
+	if !validPlusCode(short) || separator >= plusCodeSeparatorPosition {
+		return "", fmt.Errorf("%w: %q", ErrInvalidPlusCode, short)
+	}
+	if err := reference.Validate(); err != nil {
+		return "", err
+	}
+
+	paddingLen := plusCodeSeparatorPosition - separator
+	resolution := math.Pow(plusCodeBase, float64(2-paddingLen/2))
+	prefix, err := reference.PlusCode(plusCodePairLen)
+	if err != nil {
+		return "", err
+	}
+	area, err := DecodePlusCode(prefix[:paddingLen] + short)
+	if err != nil {
+		return "", err
+	}
+
+	// move the area by its resolution if the reference is nearer to the neighbor one
+	center := area.Center()
+	lat, lng := center.Lat, center.Lng
+	refLng := WrapLng(reference.Lng)
+	switch {
+	case reference.Lat+resolution/2 < lat && lat-resolution >= minLat:
+		lat -= resolution
+	case reference.Lat-resolution/2 > lat && lat+resolution <= maxLat:
+		lat += resolution
+	}
+	switch {
+	case refLng+resolution/2 < lng:
+		lng -= resolution
+	case refLng-resolution/2 > lng:
+		lng += resolution
+	}
+	return NewPoint(WrapLng(lng), lat, nil).PlusCode(area.Length)
+}
+
+// validPlusCode reports whether the code is a valid full or short Open Location Code
+func validPlusCode(code string) bool {
+	separator := strings.IndexByte(code, plusCodeSeparator)
+	if separator < 0 || separator != strings.LastIndexByte(code, plusCodeSeparator) ||
+		separator > plusCodeSeparatorPosition || separator%2 == 1 || len(code)-separator == 2 {
+		return false
+	}
+
+	if padding := strings.IndexByte(code, plusCodePadding); padding >= 0 {
+		// padding only fills a code shorter than the separator position, in pairs
+		if separator < plusCodeSeparatorPosition || padding == 0 || padding%2 == 1 ||
+			strings.Trim(code[padding:separator], string(plusCodePadding)) != "" || len(code) > separator+1 {
+			return false
+		}
+		code = code[:padding] + code[separator:]
+	}
+
+	for i := 0; i < len(code); i++ {
+		if code[i] != plusCodeSeparator && strings.IndexByte(plusCodeAlphabet, upperByte(code[i])) < 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// isFullPlusCode reports whether the code is a valid full Open Location Code within the range of the coordinates
+func isFullPlusCode(code string) bool {
+	if !validPlusCode(code) || strings.IndexByte(code, plusCodeSeparator) != plusCodeSeparatorPosition {
+		return false
+	}
+	// the first pair is at most 180 degrees of latitude and 360 degrees of longitude
+	return strings.IndexByte(plusCodeAlphabet, upperByte(code[0]))*plusCodeBase < maxLat-minLat &&
+		strings.IndexByte(plusCodeAlphabet, upperByte(code[1]))*plusCodeBase < maxLng-minLng
+}
+
+// plusCodeLatPrecision returns the degrees of latitude of a code of length digits
+func plusCodeLatPrecision(length int) float64 {
+	if length <= plusCodePairLen {
+		return math.Pow(plusCodeBase, float64(2-length/2))
+	}
+	return math.Pow(plusCodeBase, -3) / math.Pow(plusCodeGridRows, float64(length-plusCodePairLen))
+}
+
+func upperByte(c byte) byte {
+	if c >= 'a' && c <= 'z' {
+		return c - 'a' + 'A'
+	}
+	return c
+}
@@ -0,0 +1,200 @@
+package geohash
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestPoint_PlusCode(t *testing.T) {
+	tests := []struct {
+		name    string
+		p       *Point
+		length  int
+		want    string
+		wantErr error
+	}{
+		{name: "TestPoint_PlusCode 1", p: NewPoint(8.0000625, 47.0000625, nil), length: 10, want: "8FVC2222+22"},
+		{name: "TestPoint_PlusCode 2", p: NewPoint(2.7821875, 20.3700625, nil), length: 10, want: "7FG49QCJ+2V"},
+		{name: "TestPoint_PlusCode 3", p: NewPoint(2.782234375, 20.3701125, nil), length: 11, want: "7FG49QCJ+2VX"},
+		{name: "TestPoint_PlusCode 4", p: NewPoint(2.775, 20.375, nil), length: 6, want: "7FG49Q00+"},
+		{name: "TestPoint_PlusCode 5", p: NewPoint(174.7859375, -41.2730625, nil), length: 10, want: "4VCPPQGP+Q9"},
+		{name: "TestPoint_PlusCode 6", p: NewPoint(1, 90, nil), length: 4, want: "CFX30000+"},
+		{name: "TestPoint_PlusCode 7", p: NewPoint(-180, -90, nil), length: 4, want: "22220000+"},
+		{name: "TestPoint_PlusCode 8", p: NewPoint(0, 0, nil), length: 7, wantErr: ErrInvalidPlusCode},
+		{name: "TestPoint_PlusCode 9", p: NewPoint(0, 91, nil), length: 10, wantErr: ErrInvalidPoint},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.p.PlusCode(tt.length)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("PlusCode() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("PlusCode() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDecodePlusCode(t *testing.T) {
+	tests := []struct {
+		name    string
+		code    string
+		want    PlusCodeArea
+		wantErr error
+	}{
+		{
+			name: "TestDecodePlusCode 1",
+			code: "7FG49QCJ+2V",
+			want: PlusCodeArea{Rect: Rect{MinLng: 2.782125, MinLat: 20.37, MaxLng: 2.78225, MaxLat: 20.370125}, Length: 10},
+		},
+		{
+			name: "TestDecodePlusCode 2",
+			code: "7fg49q00+",
+			want: PlusCodeArea{Rect: Rect{MinLng: 2.75, MinLat: 20.35, MaxLng: 2.8, MaxLat: 20.4}, Length: 6},
+		},
+		{
+			name: "TestDecodePlusCode 3",
+			code: "7FG49QCJ+2VX",
+			want: PlusCodeArea{Rect: Rect{MinLng: 2.78221875, MinLat: 20.3701, MaxLng: 2.78225, MaxLat: 20.370125}, Length: 11},
+		},
+		{name: "TestDecodePlusCode 4", code: "9QCJ+2VX", wantErr: ErrInvalidPlusCode},
+		{name: "TestDecodePlusCode 5", code: "7FG49Q0C+", wantErr: ErrInvalidPlusCode},
+		{name: "TestDecodePlusCode 6", code: "7FG49QCJ+2", wantErr: ErrInvalidPlusCode},
+		{name: "TestDecodePlusCode 7", code: "XFG49QCJ+2V", wantErr: ErrInvalidPlusCode},
+		{name: "TestDecodePlusCode 8", code: "7FG49QCA+2V", wantErr: ErrInvalidPlusCode},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := DecodePlusCode(tt.code)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("DecodePlusCode() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if got.Length != tt.want.Length || math.Abs(got.MinLng-tt.want.MinLng) > 1e-10 || math.Abs(got.MinLat-tt.want.MinLat) > 1e-10 ||
+				math.Abs(got.MaxLng-tt.want.MaxLng) > 1e-10 || math.Abs(got.MaxLat-tt.want.MaxLat) > 1e-10 {
+				t.Errorf("DecodePlusCode() = %+v, want %+v", *got, tt.want)
+			}
+		})
+	}
+}
+
+func TestShortenPlusCode(t *testing.T) {
+	tests := []struct {
+		name      string
+		code      string
+		reference *Point
+		want      string
+	}{
+		{name: "TestShortenPlusCode 1", code: "9C3W9QCJ+2VX", reference: NewPoint(-1.217765625, 51.3701125, nil), want: "+2VX"},
+		{name: "TestShortenPlusCode 2", code: "9C3W9QCJ+2VX", reference: NewPoint(-1.217765625, 51.3708675, nil), want: "CJ+2VX"},
+		{name: "TestShortenPlusCode 3", code: "8FVC9G8F+6W", reference: NewPoint(8.5403, 47.3779, nil), want: "9G8F+6W"},
+		{name: "TestShortenPlusCode 4", code: "8FVC9G8F+6W", reference: NewPoint(-0.1278, 51.5074, nil), want: "8FVC9G8F+6W"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ShortenPlusCode(tt.code, tt.reference)
+			if err != nil {
+				t.Fatalf("ShortenPlusCode() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("ShortenPlusCode() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+	t.Run("TestShortenPlusCode padded", func(t *testing.T) {
+		if _, err := ShortenPlusCode("7FG49Q00+", NewPoint(2.775, 20.375, nil)); !errors.Is(err, ErrInvalidPlusCode) {
+			t.Errorf("ShortenPlusCode() error = %v, want %v", err, ErrInvalidPlusCode)
+		}
+	})
+}
+
+func TestRecoverPlusCode(t *testing.T) {
+	tests := []struct {
+		name      string
+		short     string
+		reference *Point
+		want      string
+		wantErr   error
+	}{
+		{name: "TestRecoverPlusCode 1", short: "9G8F+6W", reference: NewPoint(8.524997, 47.365590, nil), want: "8FVC9G8F+6W"},
+		{name: "TestRecoverPlusCode 2", short: "CJ+2VX", reference: NewPoint(-1.217765625, 51.3708675, nil), want: "9C3W9QCJ+2VX"},
+		{name: "TestRecoverPlusCode 3", short: "+2VX", reference: NewPoint(-1.217765625, 51.3701125, nil), want: "9C3W9QCJ+2VX"},
+		{name: "TestRecoverPlusCode 4", short: "8fvc9g8f+6w", reference: NewPoint(0, 0, nil), want: "8FVC9G8F+6W"},
+		{name: "TestRecoverPlusCode 5", short: "9G8F+6W", reference: nil, wantErr: ErrInvalidPoint},
+		{name: "TestRecoverPlusCode 6", short: "9G8F6W", reference: NewPoint(8.524997, 47.365590, nil), wantErr: ErrInvalidPlusCode},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := RecoverPlusCode(tt.short, tt.reference)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("RecoverPlusCode() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("RecoverPlusCode() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+	t.Run("TestRecoverPlusCode across the area", func(t *testing.T) {
+		// the reference is in the area north of the nearest code
+		got, err := RecoverPlusCode("2222+22", NewPoint(8.0000625, 47.99, nil))
+		if err != nil {
+			t.Fatalf("RecoverPlusCode() error = %v", err)
+		}
+		if got != "8FWC2222+22" {
+			t.Errorf("RecoverPlusCode() = %v, want %v", got, "8FWC2222+22")
+		}
+	})
+}
+
+func TestPlusCodeArea_Geohashes(t *testing.T) {
+	area, err := DecodePlusCode("8FVC9G8F+6W")
+	if err != nil {
+		t.Fatalf("DecodePlusCode() error = %v", err)
+	}
+
+	center := area.Center()
+	wantCell := center.Geohash()
+	got := area.Geohashes(8)
+	found := false
+	for _, g := range got {
+		bounds, _ := g.Bounds()
+		if !bounds.Intersects(area.Rect) {
+			t.Errorf("Geohashes() = %v, %v is disjoint from the area", got, g)
+		}
+		found = found || g == wantCell
+	}
+	if !found {
+		t.Errorf("Geohashes() = %v, want %v in it", got, wantCell)
+	}
+	if got := (*PlusCodeArea)(nil).Geohashes(8); got != nil {
+		t.Errorf("Geohashes() = %v, want nil", got)
+	}
+}
+
+func TestTrie_plusCode(t1 *testing.T) {
+	t := NewTrie()
+	in, out := NewPoint(8.5248, 47.36556, "in"), NewPoint(8.5255, 47.366, "out")
+	_ = t.Put(in)
+	_ = t.Put(out)
+
+	area, _ := DecodePlusCode("8FVC9G8F+6W")
+	var got []*Point
+	for _, g := range area.Geohashes(8) {
+		box, err := t.Lookup(g)
+		if err != nil {
+			continue
+		}
+		for _, p := range box.GetAllPoints() {
+			if area.Contains(p) {
+				got = append(got, p)
+			}
+		}
+	}
+	if len(got) != 1 || got[0] != in {
+		t1.Errorf("points in the area = %v, want [%v]", got, in)
+	}
+}
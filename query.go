@@ -0,0 +1,148 @@
+package geohash
+
+import "time"
+
+// CountInCircle returns the number of points within radius of center without materializing them,
+// the cells inside the circle are counted by their point counts.
+func (t *Trie) CountInCircle(center *Point, radius Distance) (uint32, error) {
+	if t == nil || t.root == nil {
+		return 0, ErrNilTrie
+	}
+	if err := center.Validate(); err != nil {
+		return 0, err
+	}
+	if radius <= 0 {
+		return 0, ErrInvalidRadius
+	}
+	center = t.normalize(center)
+
+	t.RLock()
+	defer t.RUnlock()
+
+	metric := t.distanceMetric()
+	var count uint32
+	t.walk(newCircleRegion(center, radius, metric), func(n *node, within bool) bool {
+		if within {
+			count += n.pointCount
+			return true
+		}
+		for _, p := range n.GetAllPoints() {
+			if metric.Distance(center, p) <= radius {
+				count++
+			}
+		}
+		return true
+	})
+	return count, nil
+}
+
+// AnyInCircle reports whether any point is within radius of center, it stops at the first one.
+func (t *Trie) AnyInCircle(center *Point, radius Distance) (bool, error) {
+	if t == nil || t.root == nil {
+		return false, ErrNilTrie
+	}
+	if err := center.Validate(); err != nil {
+		return false, err
+	}
+	if radius <= 0 {
+		return false, ErrInvalidRadius
+	}
+	center = t.normalize(center)
+
+	t.RLock()
+	defer t.RUnlock()
+
+	metric := t.distanceMetric()
+	found := false
+	t.walk(newCircleRegion(center, radius, metric), func(n *node, within bool) bool {
+		if within {
+			found = n.pointCount > 0
+			return !found
+		}
+		for _, p := range n.GetAllPoints() {
+			if metric.Distance(center, p) <= radius {
+				found = true
+				return false
+			}
+		}
+		return true
+	})
+	return found, nil
+}
+
+// GetPointsByRing returns the points farther than minRadius and within maxRadius of center,
+// so that a search widening from minRadius to maxRadius does not return the points found before.
+func (t *Trie) GetPointsByRing(center *Point, minRadius, maxRadius Distance) ([]*Point, error) {
+	if t == nil || t.root == nil {
+		return nil, ErrNilTrie
+	}
+	if err := center.Validate(); err != nil {
+		return nil, err
+	}
+	if minRadius < 0 || minRadius >= maxRadius {
+		return nil, ErrInvalidRadius
+	}
+	center = t.normalize(center)
+
+	t.RLock()
+	defer t.RUnlock()
+
+	metric := t.distanceMetric()
+	if minRadius == 0 {
+		return t.collect(newCircleRegion(center, maxRadius, metric)), nil
+	}
+	return t.collect(&ringRegion{inner: newCircleRegion(center, minRadius, metric), outer: newCircleRegion(center, maxRadius, metric)}), nil
+}
+
+// GetPointsBySector returns the points within radius of center,
+// whose bearing from center is between fromBearing and toBearing clockwise.
+// Bearings are in degrees clockwise from the north, a sector from 315 to 45 looks north.
+func (t *Trie) GetPointsBySector(center *Point, radius Distance, fromBearing, toBearing float64) ([]*Point, error) {
+	if t == nil || t.root == nil {
+		return nil, ErrNilTrie
+	}
+	if err := center.Validate(); err != nil {
+		return nil, err
+	}
+	if radius <= 0 {
+		return nil, ErrInvalidRadius
+	}
+	center = t.normalize(center)
+
+	t.RLock()
+	defer t.RUnlock()
+
+	return t.collect(&sectorRegion{circleRegion: newCircleRegion(center, radius, t.distanceMetric()), from: fromBearing, to: toBearing}), nil
+}
+
+// collect returns the points in the region, the caller must hold the Trie lock
+func (t *Trie) collect(r region) []*Point {
+	return t.root.collect(r)
+}
+
+// collect returns the points under the node in the region
+func (n *node) collect(r region) []*Point {
+	res := make([]*Point, 0)
+	n.walk("", r, true, func(n *node, within bool) bool {
+		if within {
+			for _, box := range n.dfs() {
+				res = append(res, box.GetAllPoints()...)
+			}
+			return true
+		}
+		for _, p := range n.GetAllPoints() {
+			if r.contains(p) {
+				res = append(res, p)
+			}
+		}
+		return true
+	})
+	return res
+}
+
+// walk walks the region from the root, the caller must hold the Trie lock.
+// The point counts include the expired points not removed yet,
+// so once a point may have expired, the points are tested one by one until RemoveExpired.
+func (t *Trie) walk(r region, fn func(n *node, within bool) bool) bool {
+	return t.root.walk("", r, len(t.expiring) == 0 || time.Now().UnixNano() < t.expiry, fn)
+}
@@ -0,0 +1,226 @@
+package geohash
+
+import (
+	"math/rand"
+	"testing"
+	"time"
+)
+
+// randomTrie puts n random points around center into a Trie
+func randomTrie(center *Point, n int, spread float64) (*Trie, []*Point) {
+	t := NewTrie()
+	r := rand.New(rand.NewSource(1))
+	points := make([]*Point, 0, n)
+	for i := 0; i < n; i++ {
+		p := NewPoint(center.Lng+(r.Float64()-0.5)*spread, center.Lat+(r.Float64()-0.5)*spread, i)
+		t.Put(p)
+		points = append(points, p)
+	}
+	return t, points
+}
+
+func TestTrie_CountInCircle(t1 *testing.T) {
+	center := NewPoint(121.506377, 31.245105, "东方明珠")
+	t, points := randomTrie(center, 5000, 0.2)
+	for _, radius := range []Distance{100, 1000, 5000, 20000} {
+		var want uint32
+		for _, p := range points {
+			if center.DistanceTo(p) <= radius {
+				want++
+			}
+		}
+		t1.Run("TestTrie_CountInCircle", func(t1 *testing.T) {
+			got, err := t.CountInCircle(center, radius)
+			if err != nil {
+				t1.Fatalf("CountInCircle() error = %v", err)
+			}
+			if got != want {
+				t1.Errorf("CountInCircle(%v) = %v, want %v", radius, got, want)
+			}
+		})
+	}
+	t1.Run("TestTrie_CountInCircle invalid", func(t1 *testing.T) {
+		if _, err := t.CountInCircle(nil, 1); err == nil {
+			t1.Errorf("CountInCircle() error = %v, wantErr %v", err, true)
+		}
+	})
+}
+
+func TestTrie_CountInCircle_antimeridian(t1 *testing.T) {
+	t := NewTrie()
+	t.Put(NewPoint(179.999, 0, "east"))
+	t.Put(NewPoint(-179.999, 0, "west"))
+	center := NewPoint(179.9995, 0, nil)
+	t1.Run("TestTrie_CountInCircle_antimeridian", func(t1 *testing.T) {
+		if got, err := t.CountInCircle(center, Kilometer); got != 2 || err != nil {
+			t1.Errorf("CountInCircle() = %v, error = %v, want %v", got, err, 2)
+		}
+		if got, err := t.GetPointsByRing(center, Meter, Kilometer); len(got) != 2 || err != nil {
+			t1.Errorf("GetPointsByRing() = %v, error = %v, want 2 points", got, err)
+		}
+		if got, err := t.GetPointsBySector(center, Kilometer, 0, 180); len(got) != 1 || err != nil {
+			t1.Errorf("GetPointsBySector() = %v, error = %v, want 1 point", got, err)
+		}
+	})
+	t1.Run("TestTrie_CountInCircle_antimeridian metric", func(t1 *testing.T) {
+		t := NewTrie(WithMetric(Spherical))
+		t.Put(NewPoint(179.999, 0, "east"))
+		t.Put(NewPoint(-179.999, 0, "west"))
+		if got, err := t.GetPointsByCircle(center, 1000); len(got) != 2 || err != nil {
+			t1.Errorf("GetPointsByCircle() = %v, error = %v, want 2 points", got, err)
+		}
+	})
+}
+
+func TestTrie_CountInCircle_expired(t1 *testing.T) {
+	t := NewTrie()
+	center := NewPoint(121.506377, 31.245105, "东方明珠")
+	t.Put(center)
+	t.PutWithTTL(NewPoint(121.506378, 31.245105, nil), time.Nanosecond)
+	time.Sleep(time.Millisecond)
+	t1.Run("TestTrie_CountInCircle_expired", func(t1 *testing.T) {
+		if got, _ := t.CountInCircle(center, 10000); got != 1 {
+			t1.Errorf("CountInCircle() = %v, want %v", got, 1)
+		}
+	})
+}
+
+func TestTrie_AnyInCircle(t1 *testing.T) {
+	t := NewTrie()
+	t.Put(NewPoint(13.361389, 38.115556, "Palermo"))
+	t.Put(NewPoint(121.506377, 31.245105, "东方明珠"))
+	tests := []struct {
+		name   string
+		center *Point
+		radius Distance
+		want   bool
+	}{
+		{
+			name:   "TestTrie_AnyInCircle 1",
+			center: NewPoint(121.4871639, 31.2388556, "上海和平饭店"),
+			radius: 2000,
+			want:   true,
+		},
+		{
+			name:   "TestTrie_AnyInCircle 2",
+			center: NewPoint(121.4871639, 31.2388556, "上海和平饭店"),
+			radius: 1000,
+			want:   false,
+		},
+		{
+			name:   "TestTrie_AnyInCircle 3",
+			center: NewPoint(0, 0, nil),
+			radius: 5000000,
+			want:   true,
+		},
+	}
+	for _, tt := range tests {
+		t1.Run(tt.name, func(t1 *testing.T) {
+			got, err := t.AnyInCircle(tt.center, tt.radius)
+			if err != nil {
+				t1.Fatalf("AnyInCircle() error = %v", err)
+			}
+			if got != tt.want {
+				t1.Errorf("AnyInCircle() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTrie_GetPointsByRing(t1 *testing.T) {
+	center := NewPoint(121.506377, 31.245105, "东方明珠")
+	t, points := randomTrie(center, 5000, 0.2)
+	tests := []struct {
+		name      string
+		minRadius Distance
+		maxRadius Distance
+		wantErr   bool
+	}{
+		{
+			name:      "TestTrie_GetPointsByRing 1",
+			minRadius: 0,
+			maxRadius: 2000,
+		},
+		{
+			name:      "TestTrie_GetPointsByRing 2",
+			minRadius: 2000,
+			maxRadius: 5000,
+		},
+		{
+			name:      "TestTrie_GetPointsByRing 3",
+			minRadius: 5000,
+			maxRadius: 5000,
+			wantErr:   true,
+		},
+	}
+	for _, tt := range tests {
+		t1.Run(tt.name, func(t1 *testing.T) {
+			got, err := t.GetPointsByRing(center, tt.minRadius, tt.maxRadius)
+			if (err != nil) != tt.wantErr {
+				t1.Fatalf("GetPointsByRing() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			want := map[*Point]struct{}{}
+			for _, p := range points {
+				if d := center.DistanceTo(p); !tt.wantErr && d <= tt.maxRadius && (tt.minRadius == 0 || d > tt.minRadius) {
+					want[p] = struct{}{}
+				}
+			}
+			assertSamePoints(t1, "GetPointsByRing()", got, want)
+		})
+	}
+}
+
+func TestTrie_GetPointsBySector(t1 *testing.T) {
+	center := NewPoint(121.506377, 31.245105, "东方明珠")
+	t, points := randomTrie(center, 5000, 0.2)
+	tests := []struct {
+		name string
+		from float64
+		to   float64
+	}{
+		{
+			name: "TestTrie_GetPointsBySector 1",
+			from: 0,
+			to:   90,
+		},
+		{
+			name: "TestTrie_GetPointsBySector 2",
+			from: 315,
+			to:   45,
+		},
+		{
+			name: "TestTrie_GetPointsBySector 3",
+			from: 0,
+			to:   360,
+		},
+	}
+	for _, tt := range tests {
+		t1.Run(tt.name, func(t1 *testing.T) {
+			got, err := t.GetPointsBySector(center, 5000, tt.from, tt.to)
+			if err != nil {
+				t1.Fatalf("GetPointsBySector() error = %v", err)
+			}
+			want := map[*Point]struct{}{}
+			for _, p := range points {
+				b := bearing(center, p)
+				inSector := tt.to-tt.from >= 360 || (tt.from < tt.to && b >= tt.from && b <= tt.to) || (tt.from > tt.to && (b >= tt.from || b <= tt.to))
+				if center.DistanceTo(p) <= 5000 && inSector {
+					want[p] = struct{}{}
+				}
+			}
+			assertSamePoints(t1, "GetPointsBySector()", got, want)
+		})
+	}
+}
+
+func assertSamePoints(t *testing.T, name string, got []*Point, want map[*Point]struct{}) {
+	t.Helper()
+	if len(got) != len(want) {
+		t.Errorf("%s returned %v points, want %v", name, len(got), len(want))
+	}
+	for _, p := range got {
+		if _, ok := want[p]; !ok {
+			t.Errorf("%s returned unexpected %v", name, p)
+		}
+	}
+}
@@ -0,0 +1,211 @@
+package geohash
+
+import "math"
+
+const metersPerDegree = earthRadius * math.Pi / 180
+
+// Rect is a rectangle in longitude/latitude space,
+// a MinLng greater than MaxLng spans the antimeridian eastward from MinLng to MaxLng.
+type Rect struct {
+	MinLng, MinLat, MaxLng, MaxLat float64
+}
+
+func (r Rect) Contains(p *Point) bool {
+	if p == nil || p.Lat < r.MinLat || p.Lat > r.MaxLat {
+		return false
+	}
+	if r.wraps() {
+		return p.Lng >= r.MinLng || p.Lng <= r.MaxLng
+	}
+	return p.Lng >= r.MinLng && p.Lng <= r.MaxLng
+}
+
+func (r Rect) Intersects(o Rect) bool {
+	if r.MinLat > o.MaxLat || o.MinLat > r.MaxLat {
+		return false
+	}
+	for _, a := range r.split() {
+		for _, b := range o.split() {
+			if a.MinLng <= b.MaxLng && b.MinLng <= a.MaxLng {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (r Rect) Center() *Point {
+	lng := (r.MinLng + r.MaxLng) / 2
+	if r.wraps() {
+		lng = WrapLng(lng + 180)
+	}
+	return NewPoint(lng, (r.MinLat+r.MaxLat)/2, nil)
+}
+
+// union returns the smallest rectangle containing both rectangles,
+// its longitudes are the shorter of the spans eastward from the west edge of either rectangle
+func (r Rect) union(o Rect) Rect {
+	res := Rect{MinLat: math.Min(r.MinLat, o.MinLat), MaxLat: math.Max(r.MaxLat, o.MaxLat)}
+
+	west, width := r.MinLng, math.Max(r.lngWidth(), lngSpan(r.MinLng, o.MinLng)+o.lngWidth())
+	if w := math.Max(o.lngWidth(), lngSpan(o.MinLng, r.MinLng)+r.lngWidth()); w < width {
+		west, width = o.MinLng, w
+	}
+	if width >= maxLng-minLng {
+		res.MinLng, res.MaxLng = minLng, maxLng
+		return res
+	}
+
+	res.MinLng, res.MaxLng = west, west+width
+	if res.MaxLng > maxLng {
+		res.MaxLng -= maxLng - minLng
+	}
+	return res
+}
+
+// wraps reports whether the rectangle spans the antimeridian
+func (r Rect) wraps() bool {
+	return r.MinLng > r.MaxLng
+}
+
+// split cuts the rectangle at the antimeridian, into two rectangles if it spans it
+func (r Rect) split() []Rect {
+	if !r.wraps() {
+		return []Rect{r}
+	}
+	east, west := r, r
+	east.MaxLng, west.MinLng = maxLng, minLng
+	return []Rect{east, west}
+}
+
+// lngWidth returns the degrees of longitude from the west to the east edge
+func (r Rect) lngWidth() float64 {
+	if r.wraps() {
+		return r.MaxLng - r.MinLng + maxLng - minLng
+	}
+	return r.MaxLng - r.MinLng
+}
+
+// lngSpan returns the degrees eastward from the longitude from to the longitude to, in [0, 360)
+func lngSpan(from, to float64) float64 {
+	return math.Mod(math.Mod(to-from, 360)+360, 360)
+}
+
+// cover returns the geohash cells of precision intersecting the rectangle, from south-west to north-east,
+// the part of a rectangle west of the antimeridian comes first
+func (r Rect) cover(precision int) []string {
+	if r.wraps() {
+		parts := r.split()
+		return append(parts[0].cover(precision), parts[1].cover(precision)...)
+	}
+
+	lngBits, latBits := cellBits(precision)
+	minLngIndex, maxLngIndex := cellRange(r.MinLng, r.MaxLng, minLng, maxLng, lngBits)
+	minLatIndex, maxLatIndex := cellRange(r.MinLat, r.MaxLat, minLat, maxLat, latBits)
+
+	res := make([]string, 0, (maxLngIndex-minLngIndex+1)*(maxLatIndex-minLatIndex+1))
+	for latIndex := minLatIndex; latIndex <= maxLatIndex; latIndex++ {
+		for lngIndex := minLngIndex; lngIndex <= maxLngIndex; lngIndex++ {
+			res = append(res, cellGeohash(lngIndex, latIndex, precision))
+		}
+	}
+	return res
+}
+
+// coverCount returns len(r.cover(precision)) without building the cells
+func (r Rect) coverCount(precision int) int {
+	if r.wraps() {
+		parts := r.split()
+		return parts[0].coverCount(precision) + parts[1].coverCount(precision)
+	}
+
+	lngBits, latBits := cellBits(precision)
+	minLngIndex, maxLngIndex := cellRange(r.MinLng, r.MaxLng, minLng, maxLng, lngBits)
+	minLatIndex, maxLatIndex := cellRange(r.MinLat, r.MaxLat, minLat, maxLat, latBits)
+	return int(maxLngIndex-minLngIndex+1) * int(maxLatIndex-minLatIndex+1)
+}
+
+// circleRect returns the rectangle enclosing the circle of radius meters around center,
+// which spans the antimeridian if the circle crosses it
+func circleRect(center *Point, radius float64) Rect {
+	difLat := radius / metersPerDegree
+	r := Rect{MinLng: minLng, MinLat: center.Lat - difLat, MaxLng: maxLng, MaxLat: center.Lat + difLat}
+	if r.MinLat <= minLat || r.MaxLat >= maxLat {
+		// the circle covers a pole, so it spans all longitudes
+		r.MinLat, r.MaxLat = math.Max(r.MinLat, minLat), math.Min(r.MaxLat, maxLat)
+		return r
+	}
+
+	// the meridians tangent to the circle, which touch it poleward of the center
+	sinDifLng := math.Sin(radius/earthRadius) / math.Cos(center.Lat*math.Pi/180)
+	if sinDifLng < 1 {
+		difLng := math.Asin(sinDifLng) * 180 / math.Pi
+		r.MinLng, r.MaxLng = center.Lng-difLng, center.Lng+difLng
+		if r.MinLng < minLng {
+			r.MinLng += maxLng - minLng
+		}
+		if r.MaxLng > maxLng {
+			r.MaxLng -= maxLng - minLng
+		}
+	}
+	return r
+}
+
+// cellBits returns the number of longitude and latitude bits of a geohash of precision
+func cellBits(precision int) (lngBits, latBits int) {
+	bits := precision * 5
+	return (bits + 1) >> 1, bits >> 1
+}
+
+// cellRange returns the indexes of the cells between the coordinates from and to, in the range [start, end] split by bits
+func cellRange(from, to, start, end float64, bits int) (uint32, uint32) {
+	cells := float64(uint32(1) << bits)
+	index := func(coordinate float64) uint32 {
+		i := math.Floor((coordinate - start) / (end - start) * cells)
+		return uint32(math.Max(0, math.Min(i, cells-1)))
+	}
+	return index(from), index(to)
+}
+
+// cellGeohash interleaves the longitude and latitude indexes of a cell into its geohash of precision
+func cellGeohash(lngIndex, latIndex uint32, precision int) string {
+	lngBits, latBits := cellBits(precision)
+	geohash := make([]byte, precision)
+	var code uint8
+	for i := 0; i < precision*5; i++ {
+		code <<= 1
+		if i&1 == 0 {
+			lngBits--
+			code |= uint8(lngIndex>>lngBits) & 1
+		} else {
+			latBits--
+			code |= uint8(latIndex>>latBits) & 1
+		}
+		if i%5 == 4 {
+			geohash[i/5] = encoder[code]
+			code = 0
+		}
+	}
+	return string(geohash)
+}
+
+// cellIndex splits a geohash of any precision into the indexes of its cell
+func cellIndex(geohash string) (lngIndex, latIndex uint32, ok bool) {
+	if len(geohash) == 0 || len(geohash) > geohashLen {
+		return 0, 0, false
+	}
+
+	for i := 0; i < len(geohash)*5; i++ {
+		code := decode(geohash[i/5])
+		if code == invalidCode {
+			return 0, 0, false
+		}
+		bit := uint32(code>>(4-i%5)) & 1
+		if i&1 == 0 {
+			lngIndex = lngIndex<<1 | bit
+		} else {
+			latIndex = latIndex<<1 | bit
+		}
+	}
+	return lngIndex, latIndex, true
+}
@@ -0,0 +1,262 @@
+package geohash
+
+import (
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestRect_Contains(t *testing.T) {
+	r := Rect{MinLng: 121, MinLat: 31, MaxLng: 122, MaxLat: 32}
+	tests := []struct {
+		name string
+		p    *Point
+		want bool
+	}{
+		{
+			name: "TestRect_Contains 1",
+			p:    NewPoint(121.506377, 31.245105, "东方明珠"),
+			want: true,
+		},
+		{
+			name: "TestRect_Contains 2",
+			p:    NewPoint(13.361389, 38.115556, "Palermo"),
+			want: false,
+		},
+		{
+			name: "TestRect_Contains 3",
+			p:    nil,
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := r.Contains(tt.p); got != tt.want {
+				t.Errorf("Contains() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	wrapped := Rect{MinLng: 179, MinLat: -1, MaxLng: -179, MaxLat: 1}
+	for _, tt := range []struct {
+		name string
+		p    *Point
+		want bool
+	}{
+		{name: "TestRect_Contains antimeridian 1", p: NewPoint(179.5, 0, nil), want: true},
+		{name: "TestRect_Contains antimeridian 2", p: NewPoint(-179.5, 0, nil), want: true},
+		{name: "TestRect_Contains antimeridian 3", p: NewPoint(0, 0, nil), want: false},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := wrapped.Contains(tt.p); got != tt.want {
+				t.Errorf("Contains() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRect_Intersects(t *testing.T) {
+	r := Rect{MinLng: 0, MinLat: 0, MaxLng: 1, MaxLat: 1}
+	tests := []struct {
+		name string
+		o    Rect
+		want bool
+	}{
+		{
+			name: "TestRect_Intersects 1",
+			o:    Rect{MinLng: 0.5, MinLat: 0.5, MaxLng: 2, MaxLat: 2},
+			want: true,
+		},
+		{
+			name: "TestRect_Intersects 2",
+			o:    Rect{MinLng: 1.5, MinLat: 0, MaxLng: 2, MaxLat: 1},
+			want: false,
+		},
+		{
+			name: "TestRect_Intersects 3",
+			o:    Rect{MinLng: 179, MinLat: 0, MaxLng: 0.5, MaxLat: 1},
+			want: true,
+		},
+		{
+			name: "TestRect_Intersects 4",
+			o:    Rect{MinLng: 179, MinLat: 0, MaxLng: -179, MaxLat: 1},
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := r.Intersects(tt.o); got != tt.want {
+				t.Errorf("Intersects() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRect_cover(t *testing.T) {
+	tests := []struct {
+		name      string
+		r         Rect
+		precision int
+		want      []string
+	}{
+		{
+			name:      "TestRect_cover 1",
+			r:         Rect{MinLng: -0.0001, MinLat: -0.0001, MaxLng: 0.0001, MaxLat: 0.0001},
+			precision: 6,
+			want:      []string{"7ZZZZZ", "KPBPBP", "EBPBPB", "S00000"},
+		},
+		{
+			name:      "TestRect_cover antimeridian",
+			r:         Rect{MinLng: 179.9, MinLat: 0.1, MaxLng: -179.9, MaxLat: 0.2},
+			precision: 2,
+			want:      []string{"XB", "80"},
+		},
+		{
+			name:      "TestRect_cover 2",
+			r:         Rect{MinLng: 121.506377, MinLat: 31.245105, MaxLng: 121.506377, MaxLat: 31.245105},
+			precision: 8,
+			want:      []string{"WTW3SZYP"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.r.cover(tt.precision)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("cover() = %v, want %v", got, tt.want)
+			}
+			if count := tt.r.coverCount(tt.precision); count != len(got) {
+				t.Errorf("coverCount() = %v, want %v", count, len(got))
+			}
+		})
+	}
+}
+
+func TestRect_Center(t *testing.T) {
+	tests := []struct {
+		name string
+		r    Rect
+		want *Point
+	}{
+		{name: "TestRect_Center 1", r: Rect{MinLng: 0, MinLat: 0, MaxLng: 2, MaxLat: 2}, want: NewPoint(1, 1, nil)},
+		{name: "TestRect_Center 2", r: Rect{MinLng: 178, MinLat: 0, MaxLng: -170, MaxLat: 2}, want: NewPoint(-176, 1, nil)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.r.Center(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Center() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRect_union(t *testing.T) {
+	tests := []struct {
+		name string
+		r, o Rect
+		want Rect
+	}{
+		{
+			name: "TestRect_union 1",
+			r:    Rect{MinLng: 0, MinLat: 0, MaxLng: 1, MaxLat: 1},
+			o:    Rect{MinLng: 2, MinLat: -1, MaxLng: 3, MaxLat: 0.5},
+			want: Rect{MinLng: 0, MinLat: -1, MaxLng: 3, MaxLat: 1},
+		},
+		{
+			name: "TestRect_union 2",
+			r:    Rect{MinLng: 178, MinLat: 0, MaxLng: 179, MaxLat: 1},
+			o:    Rect{MinLng: -179, MinLat: 0, MaxLng: -178, MaxLat: 1},
+			want: Rect{MinLng: 178, MinLat: 0, MaxLng: -178, MaxLat: 1},
+		},
+		{
+			name: "TestRect_union 3",
+			r:    Rect{MinLng: 179, MinLat: 0, MaxLng: -179, MaxLat: 1},
+			o:    Rect{MinLng: -90, MinLat: 0, MaxLng: 100, MaxLat: 1},
+			want: Rect{MinLng: -90, MinLat: 0, MaxLng: -179, MaxLat: 1},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.r.union(tt.o); got != tt.want {
+				t.Errorf("union() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_circleRect(t *testing.T) {
+	tests := []struct {
+		name   string
+		center *Point
+		radius float64
+		want   Rect
+	}{
+		{
+			name:   "Test_circleRect 1",
+			center: NewPoint(0, 0, nil),
+			radius: metersPerDegree,
+			want:   Rect{MinLng: -1, MinLat: -1, MaxLng: 1, MaxLat: 1},
+		},
+		{
+			name:   "Test_circleRect 2",
+			center: NewPoint(10, 89.5, nil),
+			radius: metersPerDegree,
+			want:   Rect{MinLng: minLng, MinLat: 88.5, MaxLng: maxLng, MaxLat: maxLat},
+		},
+		{
+			name:   "Test_circleRect 3",
+			center: NewPoint(179.5, 0, nil),
+			radius: metersPerDegree,
+			want:   Rect{MinLng: 178.5, MinLat: -1, MaxLng: -179.5, MaxLat: 1},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := circleRect(tt.center, tt.radius)
+			for _, v := range [][2]float64{{got.MinLng, tt.want.MinLng}, {got.MinLat, tt.want.MinLat}, {got.MaxLng, tt.want.MaxLng}, {got.MaxLat, tt.want.MaxLat}} {
+				if math.Abs(v[0]-v[1]) > 1e-9 {
+					t.Errorf("circleRect() = %v, want %v", got, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func Test_cellIndex(t *testing.T) {
+	tests := []struct {
+		name    string
+		geohash string
+		wantOk  bool
+	}{
+		{
+			name:    "Test_cellIndex 1",
+			geohash: "WTW3SZYP",
+			wantOk:  true,
+		},
+		{
+			name:    "Test_cellIndex 2",
+			geohash: "SQC",
+			wantOk:  true,
+		},
+		{
+			name:    "Test_cellIndex 3",
+			geohash: "A",
+			wantOk:  false,
+		},
+		{
+			name:    "Test_cellIndex 4",
+			geohash: "",
+			wantOk:  false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lngIndex, latIndex, ok := cellIndex(tt.geohash)
+			if ok != tt.wantOk {
+				t.Fatalf("cellIndex() ok = %v, want %v", ok, tt.wantOk)
+			}
+			if ok && cellGeohash(lngIndex, latIndex, len(tt.geohash)) != tt.geohash {
+				t.Errorf("cellGeohash() = %v, want %v", cellGeohash(lngIndex, latIndex, len(tt.geohash)), tt.geohash)
+			}
+		})
+	}
+}
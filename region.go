@@ -0,0 +1,147 @@
+package geohash
+
+import "math"
+
+const (
+	disjoint relation = iota
+	intersects
+	within
+)
+
+type (
+	// relation is the position of a geohash cell relative to a query region
+	relation uint8
+
+	// region is the shape of a query, it prunes the cells of the Trie and then filters the points
+	region interface {
+		relate(cell Rect) relation
+		contains(p *Point) bool
+	}
+
+	circleRegion struct {
+		center *Point
+		radius Distance
+		metric Metric
+		bounds Rect
+	}
+
+	// ringRegion is the annulus between the circles, excluding the inner one
+	ringRegion struct {
+		inner, outer *circleRegion
+	}
+
+	// sectorRegion is the part of the circle between the bearings, clockwise from from to to
+	sectorRegion struct {
+		*circleRegion
+		from, to float64
+	}
+)
+
+func newCircleRegion(center *Point, radius Distance, metric Metric) *circleRegion {
+	return &circleRegion{center: center, radius: radius, metric: metric, bounds: metric.Bound(center, radius)}
+}
+
+func (c *circleRegion) relate(cell Rect) relation {
+	if !cell.Intersects(c.bounds) {
+		return disjoint
+	}
+	for _, p := range cell.outline() {
+		if !c.contains(p) {
+			return intersects
+		}
+	}
+	return within
+}
+
+func (c *circleRegion) contains(p *Point) bool {
+	return c.metric.Distance(c.center, p) <= c.radius
+}
+
+func (r *ringRegion) relate(cell Rect) relation {
+	rel := r.outer.relate(cell)
+	if rel == disjoint || r.inner.relate(cell) == within {
+		return disjoint
+	}
+	if rel == within && cell.Intersects(r.inner.bounds) {
+		return intersects
+	}
+	return rel
+}
+
+func (r *ringRegion) contains(p *Point) bool {
+	return r.outer.contains(p) && !r.inner.contains(p)
+}
+
+func (s *sectorRegion) relate(cell Rect) relation {
+	if s.circleRegion.relate(cell) == disjoint {
+		return disjoint
+	}
+	return intersects
+}
+
+func (s *sectorRegion) contains(p *Point) bool {
+	if !s.circleRegion.contains(p) {
+		return false
+	}
+	if s.center.Lng == p.Lng && s.center.Lat == p.Lat {
+		return true
+	}
+	width := s.to - s.from
+	if width >= 360 {
+		return true
+	}
+	return normalizeBearing(bearing(s.center, p)-s.from) <= normalizeBearing(width)
+}
+
+// normalizeBearing maps the bearing in degrees into [0, 360)
+func normalizeBearing(bearing float64) float64 {
+	return math.Mod(math.Mod(bearing, 360)+360, 360)
+}
+
+// outline returns the corners of the rectangle and the midpoints of its parallel edges,
+// which bulge poleward, a convex region containing them contains the rectangle.
+func (r Rect) outline() [6]*Point {
+	midLng := (r.MinLng + r.MaxLng) / 2
+	return [6]*Point{
+		NewPoint(r.MinLng, r.MinLat, nil),
+		NewPoint(midLng, r.MinLat, nil),
+		NewPoint(r.MaxLng, r.MinLat, nil),
+		NewPoint(r.MinLng, r.MaxLat, nil),
+		NewPoint(midLng, r.MaxLat, nil),
+		NewPoint(r.MaxLng, r.MaxLat, nil),
+	}
+}
+
+// walk passes the nodes under the node of prefix in the region to fn, ordered by geohash:
+// a node within the region with within = true, and a leaf intersecting it with within = false.
+// If the point counts cannot be trusted, the nodes within the region are walked down to their leaves as well.
+// It stops as soon as fn returns false, and reports whether it went through.
+func (n *node) walk(prefix string, r region, countable bool, fn func(n *node, within bool) bool) bool {
+	for i := 0; i < len(n.children); i++ {
+		child := n.children[i]
+		if child == nil {
+			continue
+		}
+
+		cell := prefix + string(encoder[i])
+		bounds, _ := Geohash(cell).Bounds()
+		rel := r.relate(bounds)
+		switch {
+		case rel == disjoint:
+			continue
+		case rel == within && countable:
+			if !fn(child, true) {
+				return false
+			}
+		case child.isLeaf:
+			if !fn(child, false) {
+				return false
+			}
+		default:
+			if !child.walk(cell, r, countable, fn) {
+				return false
+			}
+		}
+	}
+	return true
+}
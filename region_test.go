@@ -0,0 +1,65 @@
+package geohash
+
+import "testing"
+
+func Test_circleRegion_relate(t *testing.T) {
+	c := newCircleRegion(NewPoint(121.506377, 31.245105, "东方明珠"), 1000, Spherical)
+	tests := []struct {
+		name string
+		cell Geohash
+		want relation
+	}{
+		{
+			name: "Test_circleRegion_relate 1",
+			cell: "WTW3SZYP",
+			want: within,
+		},
+		{
+			name: "Test_circleRegion_relate 2",
+			cell: "WTW3SZ",
+			want: intersects,
+		},
+		{
+			name: "Test_circleRegion_relate 3",
+			cell: "SQC",
+			want: disjoint,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bounds, _ := tt.cell.Bounds()
+			if got := c.relate(bounds); got != tt.want {
+				t.Errorf("relate() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_node_walk(t1 *testing.T) {
+	t := NewTrie()
+	t.Put(NewPoint(13.361389, 38.115556, "Palermo"))
+	t.Put(NewPoint(121.506377, 31.245105, "东方明珠"))
+	t.Put(NewPoint(121.4871639, 31.2388556, "上海和平饭店"))
+	c := newCircleRegion(NewPoint(121.506377, 31.245105, "东方明珠"), 5000, Spherical)
+	t1.Run("Test_node_walk", func(t1 *testing.T) {
+		var visited int
+		if got := t.root.walk("", c, true, func(n *node, within bool) bool {
+			visited++
+			return false
+		}); got != false || visited != 1 {
+			t1.Errorf("walk() = %v, visited %v, want %v, 1", got, visited, false)
+		}
+
+		var count uint32
+		t.root.walk("", c, false, func(n *node, within bool) bool {
+			if within || !n.isLeaf {
+				t1.Errorf("walk() passed a node within the region")
+			}
+			count += n.pointCount
+			return true
+		})
+		if count != 2 {
+			t1.Errorf("walk() count = %v, want %v", count, 2)
+		}
+	})
+}
@@ -0,0 +1,186 @@
+package geohash
+
+import "time"
+
+// ShardedTrie partitions boxes into Tries by the first geohash character.
+// Each shard is guarded by its own lock, so writes to different shards never contend,
+// queries spanning several shards are fanned out transparently.
+type ShardedTrie struct {
+	shards [32]*Trie // base32
+}
+
+// NewShardedTrie creates a ShardedTrie whose shards are configured by opts, see NewTrie.
+func NewShardedTrie(opts ...TrieOption) *ShardedTrie {
+	s := &ShardedTrie{}
+	for i := 0; i < len(s.shards); i++ {
+		s.shards[i] = NewTrie(opts...)
+	}
+	return s
+}
+
+func (s *ShardedTrie) Get(geohash Geohash) (*Box, bool) {
+	return s.shard(string(geohash)).Get(geohash)
+}
+
+// Lookup is Get returning ErrInvalidGeohash for an invalid geohash and ErrNotFound for an absent box.
+func (s *ShardedTrie) Lookup(geohash Geohash) (*Box, error) {
+	return s.shard(string(geohash)).Lookup(geohash)
+}
+
+func (s *ShardedTrie) GetByPrefix(prefix string) []*Box {
+	return s.shard(prefix).GetByPrefix(prefix)
+}
+
+func (s *ShardedTrie) Put(point *Point) error {
+	if s == nil {
+		return ErrNilTrie
+	}
+	// the shard is chosen by the geohash in the datum of the shards
+	point, err := s.shards[0].normalizeValid(point)
+	if err != nil {
+		return err
+	}
+	return s.shard(string(point.Geohash())).Put(point)
+}
+
+// PutWithTTL puts the point which expires after ttl, see Trie.PutWithTTL.
+func (s *ShardedTrie) PutWithTTL(point *Point, ttl time.Duration) error {
+	if s == nil {
+		return ErrNilTrie
+	}
+	point, err := s.shards[0].normalizeValid(point)
+	if err != nil {
+		return err
+	}
+	return s.shard(string(point.Geohash())).PutWithTTL(point, ttl)
+}
+
+func (s *ShardedTrie) Delete(geohash Geohash) bool {
+	return s.shard(string(geohash)).Delete(geohash)
+}
+
+// Remove is Delete returning ErrInvalidGeohash for an invalid geohash and ErrNotFound for an absent box.
+func (s *ShardedTrie) Remove(geohash Geohash) error {
+	return s.shard(string(geohash)).Remove(geohash)
+}
+
+// Move replaces the point from with the point to, it returns false if from is not in the ShardedTrie.
+// The point to expires when from would have, a move between shards locks both of them.
+func (s *ShardedTrie) Move(from, to *Point) bool {
+	if s == nil || from == nil {
+		return false
+	}
+	to, err := s.shards[0].normalizeValid(to)
+	if err != nil {
+		return false
+	}
+	from = s.shards[0].normalize(from)
+
+	src, dst := decode(from.Geohash()[0]), decode(to.Geohash()[0])
+	if src == dst {
+		return s.shards[src].Move(from, to)
+	}
+
+	// lock the shards in order, so that crossing moves never deadlock
+	first, second := s.shards[src], s.shards[dst]
+	if src > dst {
+		first, second = second, first
+	}
+	first.Lock()
+	defer first.Unlock()
+	second.Lock()
+	defer second.Unlock()
+
+	source := s.shards[src]
+	deadline := source.root.deadline(from)
+	if !source.root.remove(from) {
+		return false
+	}
+	source.forget(from.Geohash())
+	s.shards[dst].putWithDeadline(to, deadline)
+	return true
+}
+
+// RemoveExpired removes the expired points of every shard, it returns the number of points removed.
+func (s *ShardedTrie) RemoveExpired() int {
+	if s == nil {
+		return 0
+	}
+
+	var count int
+	for _, shard := range s.shards {
+		count += shard.RemoveExpired()
+	}
+	return count
+}
+
+// GetPointsByCircle returns the points within radius meters of center, measured by the Metric of the shards.
+func (s *ShardedTrie) GetPointsByCircle(center *Point, radius uint32) ([]*Point, error) {
+	if s == nil {
+		return nil, ErrNilTrie
+	}
+	if err := center.Validate(); err != nil {
+		return nil, err
+	}
+	if radius == 0 {
+		return nil, ErrInvalidRadius
+	}
+	center = s.shards[0].normalize(center)
+
+	// every shard intersecting the circle is locked exactly once
+	r := newCircleRegion(center, Distance(radius), s.shards[0].distanceMetric())
+	res := make([]*Point, 0)
+	for i, shard := range s.shards {
+		bounds, _ := Geohash(encoder[i]).Bounds()
+		if r.relate(bounds) == disjoint {
+			continue
+		}
+		shard.RLock()
+		res = append(res, shard.collect(r)...)
+		shard.RUnlock()
+	}
+
+	return res, nil
+}
+
+// Count returns the number of boxes, see CountPoints for the number of points.
+func (s *ShardedTrie) Count() uint32 {
+	if s == nil {
+		return 0
+	}
+
+	var count uint32
+	for _, shard := range s.shards {
+		count += shard.Count()
+	}
+	return count
+}
+
+func (s *ShardedTrie) CountPoints() uint32 {
+	if s == nil {
+		return 0
+	}
+
+	var count uint32
+	for _, shard := range s.shards {
+		count += shard.CountPoints()
+	}
+	return count
+}
+
+func (s *ShardedTrie) CountByPrefix(prefix string) uint32 {
+	return s.shard(prefix).CountByPrefix(prefix)
+}
+
+// shard returns the Trie responsible for the prefix, nil if the prefix is invalid
+func (s *ShardedTrie) shard(prefix string) *Trie {
+	if s == nil || len(prefix) == 0 {
+		return nil
+	}
+
+	index := decode(prefix[0])
+	if index == invalidCode {
+		return nil
+	}
+	return s.shards[index]
+}
@@ -0,0 +1,267 @@
+package geohash
+
+import (
+	"errors"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestShardedTrie_Get(t1 *testing.T) {
+	t := NewShardedTrie()
+	p1 := NewPoint(13.361389, 38.115556, "Palermo")
+	p2 := NewPoint(121.506377, 31.245105, "东方明珠")
+	t.Put(p1)
+	t.Put(p2)
+	t1.Run("TestShardedTrie_Get", func(t1 *testing.T) {
+		_, got := t.Get(NewPoint(121.4871639, 31.2388556, "上海和平饭店").Geohash())
+		if got != false {
+			t1.Errorf("Get() want %v", false)
+		}
+		got1, got2 := t.Get(p1.Geohash())
+		if got2 != true {
+			t1.Errorf("Get() got1 = %v, want %v", got1, true)
+		}
+		if _, got := t.Get("?"); got != false {
+			t1.Errorf("Get() want %v", false)
+		}
+	})
+}
+
+func TestShardedTrie_GetByPrefix(t1 *testing.T) {
+	t := NewShardedTrie()
+	p1 := NewPoint(13.361389, 38.115556, "Palermo")
+	p2 := NewPoint(121.506377, 31.245105, "东方明珠")
+	t.Put(p1)
+	t.Put(p2)
+	t1.Run("TestShardedTrie_GetByPrefix", func(t1 *testing.T) {
+		want := []*Box{NewBox(p1.Geohash(), map[string]*Point{p1.key(): p1})}
+		if got := t.GetByPrefix(string(p1.Geohash())[:7]); !reflect.DeepEqual(got, want) {
+			t1.Errorf("GetByPrefix() = %v, want %v", got, want)
+		}
+		if got := t.GetByPrefix("?"); got != nil {
+			t1.Errorf("GetByPrefix() = %v, want %v", got, nil)
+		}
+	})
+}
+
+func TestShardedTrie_Delete(t1 *testing.T) {
+	t := NewShardedTrie()
+	p1 := NewPoint(13.361389, 38.115556, "Palermo")
+	p2 := NewPoint(121.506377, 31.245105, "东方明珠")
+	t.Put(p1)
+	t.Put(p2)
+	t1.Run("TestShardedTrie_Delete", func(t1 *testing.T) {
+		if got := t.Delete("SQC8B49R"); got != true {
+			t1.Errorf("Delete() = %v, want %v", got, true)
+		}
+		if got := t.Delete("WTW3SZYP"); got != true {
+			t1.Errorf("Delete() = %v, want %v", got, true)
+		}
+		if got := t.Delete("WTW3SZYP"); got != false {
+			t1.Errorf("Delete() = %v, want %v", got, false)
+		}
+		if got := t.Count(); got != 0 {
+			t1.Errorf("Count() = %v, want %v", got, 0)
+		}
+	})
+}
+
+func TestShardedTrie_Remove(t1 *testing.T) {
+	t := NewShardedTrie()
+	p1 := NewPoint(13.361389, 38.115556, "Palermo")
+	t.Put(p1)
+	t1.Run("TestShardedTrie_Remove", func(t1 *testing.T) {
+		if _, err := t.Lookup(p1.Geohash()); err != nil {
+			t1.Errorf("Lookup() error = %v, want %v", err, nil)
+		}
+		if err := t.Remove("?"); !errors.Is(err, ErrInvalidGeohash) {
+			t1.Errorf("Remove() error = %v, want %v", err, ErrInvalidGeohash)
+		}
+		if err := t.Remove(p1.Geohash()); err != nil {
+			t1.Errorf("Remove() error = %v, want %v", err, nil)
+		}
+		if _, err := t.Lookup(p1.Geohash()); !errors.Is(err, ErrNotFound) {
+			t1.Errorf("Lookup() error = %v, want %v", err, ErrNotFound)
+		}
+	})
+}
+
+func TestShardedTrie_Move(t1 *testing.T) {
+	t := NewShardedTrie()
+	p1 := NewPoint(13.361389, 38.115556, "Palermo")
+	p2 := NewPoint(121.506377, 31.245105, "东方明珠")
+	t.PutWithTTL(p1, time.Hour)
+	t1.Run("TestShardedTrie_Move", func(t1 *testing.T) {
+		if got := t.Move(p1, p2); got != true {
+			t1.Errorf("Move() = %v, want %v", got, true)
+		}
+		if got := t.Move(p1, p2); got != false {
+			t1.Errorf("Move() = %v, want %v", got, false)
+		}
+		if got := t.CountByPrefix("S"); got != 0 {
+			t1.Errorf("CountByPrefix() = %v, want %v", got, 0)
+		}
+		box, err := t.Lookup(p2.Geohash())
+		if err != nil || len(box.GetAllPoints()) != 1 {
+			t1.Fatalf("Lookup() = %v, %v, want %v", box, err, p2)
+		}
+		if deadline := box.expireAt[p2.key()]; deadline == 0 {
+			t1.Errorf("Move() deadline = %v, want the one of %v", deadline, p1)
+		}
+	})
+}
+
+func TestShardedTrie_options(t1 *testing.T) {
+	t := NewShardedTrie(WithDatum(GCJ02), WithMetric(Manhattan{}))
+	gps := NewPoint(121.506377, 31.245105, "东方明珠")
+	t.Put(gps)
+	t1.Run("TestShardedTrie_options", func(t1 *testing.T) {
+		box, err := t.Lookup(gps.ToDatum(GCJ02).Geohash())
+		if err != nil {
+			t1.Fatalf("Lookup() error = %v, want the point in %v", err, GCJ02)
+		}
+		for _, p := range box.GetAllPoints() {
+			if p.Datum != GCJ02 {
+				t1.Errorf("Lookup() datum = %v, want %v", p.Datum, GCJ02)
+			}
+		}
+		// 10 meters both north and east are 20 along the axes
+		center := NewPoint(gps.Lng+10/metersPerDegree, gps.Lat+10/metersPerDegree, nil)
+		if got, _ := t.GetPointsByCircle(center, 19); len(got) != 0 {
+			t1.Errorf("GetPointsByCircle() = %v, want none", got)
+		}
+		if got, _ := t.GetPointsByCircle(center, 21); len(got) != 1 {
+			t1.Errorf("GetPointsByCircle() = %v, want %v", got, gps)
+		}
+	})
+}
+
+func TestShardedTrie_RemoveExpired(t1 *testing.T) {
+	t := NewShardedTrie()
+	t.PutWithTTL(NewPoint(13.361389, 38.115556, "Palermo"), time.Nanosecond)
+	t.PutWithTTL(NewPoint(121.506377, 31.245105, "东方明珠"), time.Nanosecond)
+	time.Sleep(time.Millisecond)
+	t1.Run("TestShardedTrie_RemoveExpired", func(t1 *testing.T) {
+		if got := t.RemoveExpired(); got != 2 {
+			t1.Errorf("RemoveExpired() = %v, want %v", got, 2)
+		}
+		if got := t.Count(); got != 0 {
+			t1.Errorf("Count() = %v, want %v", got, 0)
+		}
+	})
+}
+
+func TestShardedTrie_GetPointsByCircle(t1 *testing.T) {
+	t := NewShardedTrie()
+	p1 := NewPoint(13.361389, 38.115556, "Palermo")
+	p2 := NewPoint(121.506377, 31.245105, "东方明珠")
+	// the circle around the origin spans the shards '7', 'E', 'K' and 'S'
+	p3 := NewPoint(0.0001, 0.0001, "NE")
+	p4 := NewPoint(-0.0001, -0.0001, "SW")
+	t.Put(p1)
+	t.Put(p2)
+	t.Put(p3)
+	t.Put(p4)
+	t1.Run("TestShardedTrie_GetPointsByCircle 1", func(t1 *testing.T) {
+		got, err := t.GetPointsByCircle(p1, 1)
+		if err != nil {
+			t1.Errorf("GetPointsByCircle() error = %v, wantErr %v", err, nil)
+			return
+		}
+		if !reflect.DeepEqual(got, []*Point{p1}) {
+			t1.Errorf("GetPointsByCircle() got = %v, want %v", got, []*Point{p1})
+		}
+	})
+	t1.Run("TestShardedTrie_GetPointsByCircle 2", func(t1 *testing.T) {
+		got, err := t.GetPointsByCircle(NewPoint(0, 0, nil), 100)
+		if err != nil {
+			t1.Errorf("GetPointsByCircle() error = %v, wantErr %v", err, nil)
+			return
+		}
+		if len(got) != 2 {
+			t1.Errorf("GetPointsByCircle() got = %v, want %v", got, []*Point{p3, p4})
+		}
+	})
+	t1.Run("TestShardedTrie_GetPointsByCircle 3", func(t1 *testing.T) {
+		if _, err := t.GetPointsByCircle(nil, 100); err == nil {
+			t1.Errorf("GetPointsByCircle() error = %v, wantErr %v", err, true)
+		}
+	})
+	t1.Run("TestShardedTrie_GetPointsByCircle 4", func(t1 *testing.T) {
+		got, err := t.GetPointsByCircle(NewPoint(13.9, 38.1, nil), 100000)
+		if err != nil {
+			t1.Errorf("GetPointsByCircle() error = %v, wantErr %v", err, nil)
+			return
+		}
+		if !reflect.DeepEqual(got, []*Point{p1}) {
+			t1.Errorf("GetPointsByCircle() got = %v, want %v", got, []*Point{p1})
+		}
+	})
+}
+
+func TestShardedTrie_Count(t1 *testing.T) {
+	t := NewShardedTrie()
+	var wg sync.WaitGroup
+	for i := 0; i < 100; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			t.Put(NewPoint(float64(i*3-150), float64(i-50), i))
+		}(i)
+	}
+	wg.Wait()
+	t1.Run("TestShardedTrie_Count", func(t1 *testing.T) {
+		if got := t.Count(); got != 100 {
+			t1.Errorf("Count() = %v, want %v", got, 100)
+		}
+		if got := t.CountPoints(); got != 100 {
+			t1.Errorf("CountPoints() = %v, want %v", got, 100)
+		}
+		if got := t.CountByPrefix("S"); got != t.shards[decode('S')].CountPoints() {
+			t1.Errorf("CountByPrefix() = %v, want %v", got, t.shards[decode('S')].CountPoints())
+		}
+	})
+}
+
+func TestShardedTrie_concurrency(t1 *testing.T) {
+	t := NewShardedTrie()
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+
+		var wg sync.WaitGroup
+		for w := 0; w < 8; w++ {
+			wg.Add(2)
+			go func(w int) {
+				defer wg.Done()
+				for i := 0; i < 500; i++ {
+					p := NewPoint(float64(w*500+i)/1e5-0.02, float64(i)/1e5-0.0025, i)
+					t.Put(p)
+					if i%3 == 0 {
+						t.Delete(p.Geohash())
+					}
+				}
+			}(w)
+			go func() {
+				defer wg.Done()
+				for i := 0; i < 500; i++ {
+					if _, err := t.GetPointsByCircle(NewPoint(0, 0, nil), 5000); err != nil {
+						t1.Errorf("GetPointsByCircle() error = %v", err)
+						return
+					}
+					t.Count()
+				}
+			}()
+		}
+		wg.Wait()
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(30 * time.Second):
+		t1.Fatal("deadlock under mixed load")
+	}
+}
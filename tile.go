@@ -0,0 +1,172 @@
+package geohash
+
+import (
+	"fmt"
+	"math"
+)
+
+const maxTileZoom = 30
+
+type (
+	// Tile is a Web Mercator tile of a slippy map, X grows to the east and Y to the south from the top left tile 0/0/0.
+	Tile struct {
+		X, Y, Z int
+	}
+
+	// tileRegion is the set of points in a tile, its west and north edges included
+	tileRegion struct {
+		tile   Tile
+		bounds Rect // the tile extended to the pole if it is on the edge of the map
+	}
+)
+
+// Tile returns the tile of zoom containing the point, zoom is limited to [0, 30].
+// The latitudes beyond the Web Mercator limit of about ±85.0511 fall into the tiles on the edge of the map.
+func (p *Point) Tile(zoom int) Tile {
+	zoom = int(math.Max(0, math.Min(float64(zoom), maxTileZoom)))
+	if p == nil {
+		return Tile{Z: zoom}
+	}
+
+	n := float64(uint32(1) << zoom)
+	radianLat := p.Lat * math.Pi / 180
+	x := math.Floor((p.Lng - minLng) / (maxLng - minLng) * n)
+	y := math.Floor((1 - math.Asinh(math.Tan(radianLat))/math.Pi) / 2 * n)
+	if math.IsNaN(y) {
+		y = 0
+	}
+	return Tile{
+		X: int(math.Max(0, math.Min(x, n-1))),
+		Y: int(math.Max(0, math.Min(y, n-1))),
+		Z: zoom,
+	}
+}
+
+// Tile returns the tile of zoom containing the center of the geohash cell, it returns false for an invalid geohash.
+func (g Geohash) Tile(zoom int) (Tile, bool) {
+	bounds, ok := g.Bounds()
+	if !ok {
+		return Tile{}, false
+	}
+	return bounds.Center().Tile(zoom), true
+}
+
+// ParseQuadkey parses a Bing Maps quadkey, whose length is the zoom of the tile.
+func ParseQuadkey(quadkey string) (Tile, error) {
+	if len(quadkey) > maxTileZoom {
+		return Tile{}, fmt.Errorf("%w: quadkey %q", ErrInvalidTile, quadkey)
+	}
+
+	t := Tile{Z: len(quadkey)}
+	for i := 0; i < len(quadkey); i++ {
+		digit := quadkey[i] - '0'
+		if digit > 3 {
+			return Tile{}, fmt.Errorf("%w: quadkey %q", ErrInvalidTile, quadkey)
+		}
+		t.X = t.X<<1 | int(digit&1)
+		t.Y = t.Y<<1 | int(digit>>1)
+	}
+	return t, nil
+}
+
+// Quadkey returns the Bing Maps quadkey of the tile, one base-4 digit per zoom level.
+func (t Tile) Quadkey() string {
+	if !t.valid() {
+		return ""
+	}
+
+	quadkey := make([]byte, t.Z)
+	for i := 0; i < t.Z; i++ {
+		mask := 1 << (t.Z - 1 - i)
+		digit := byte('0')
+		if t.X&mask != 0 {
+			digit++
+		}
+		if t.Y&mask != 0 {
+			digit += 2
+		}
+		quadkey[i] = digit
+	}
+	return string(quadkey)
+}
+
+// Bounds returns the rectangle of the tile.
+func (t Tile) Bounds() Rect {
+	n := float64(uint32(1) << t.Z)
+	return Rect{
+		MinLng: float64(t.X)/n*(maxLng-minLng) + minLng,
+		MinLat: tileLat(float64(t.Y+1), n),
+		MaxLng: float64(t.X+1)/n*(maxLng-minLng) + minLng,
+		MaxLat: tileLat(float64(t.Y), n),
+	}
+}
+
+// Geohashes returns the geohash cells of precision intersecting the tile.
+func (t Tile) Geohashes(precision int) []Geohash {
+	if !t.valid() || precision < 1 || precision > geohashLen {
+		return nil
+	}
+
+	cells := t.Bounds().cover(precision)
+	res := make([]Geohash, 0, len(cells))
+	for _, cell := range cells {
+		res = append(res, Geohash(cell))
+	}
+	return res
+}
+
+func (t Tile) String() string {
+	return fmt.Sprintf("%d/%d/%d", t.Z, t.X, t.Y)
+}
+
+func (t Tile) valid() bool {
+	return t.Z >= 0 && t.Z <= maxTileZoom && t.X >= 0 && t.X < 1<<t.Z && t.Y >= 0 && t.Y < 1<<t.Z
+}
+
+// GetPointsByTile returns the points in the tile, each point is in exactly one tile of a zoom.
+// The tile is in the datum of the Trie, like the tiles of a map drawn in it.
+func (t *Trie) GetPointsByTile(tile Tile) ([]*Point, error) {
+	if t == nil || t.root == nil {
+		return nil, ErrNilTrie
+	}
+	if !tile.valid() {
+		return nil, ErrInvalidTile
+	}
+
+	t.RLock()
+	defer t.RUnlock()
+
+	return t.collect(newTileRegion(tile)), nil
+}
+
+func newTileRegion(tile Tile) *tileRegion {
+	bounds := tile.Bounds()
+	if tile.Y == 0 {
+		bounds.MaxLat = maxLat
+	}
+	if tile.Y == 1<<tile.Z-1 {
+		bounds.MinLat = minLat
+	}
+	return &tileRegion{tile: tile, bounds: bounds}
+}
+
+// relate reports a cell within the tile only if no edge of the cell is on the south or east edge of the tile,
+// whose points belong to the next tile
+func (r *tileRegion) relate(cell Rect) relation {
+	if !cell.Intersects(r.bounds) {
+		return disjoint
+	}
+	if cell.MinLng >= r.bounds.MinLng && cell.MaxLng <= r.bounds.MaxLng && cell.MinLat > r.bounds.MinLat && cell.MaxLat <= r.bounds.MaxLat {
+		return within
+	}
+	return intersects
+}
+
+func (r *tileRegion) contains(p *Point) bool {
+	return p.Tile(r.tile.Z) == r.tile
+}
+
+// tileLat returns the latitude of the top edge of the tile row y out of n rows
+func tileLat(y, n float64) float64 {
+	return math.Atan(math.Sinh(math.Pi*(1-2*y/n))) * 180 / math.Pi
+}
@@ -0,0 +1,165 @@
+package geohash
+
+import (
+	"errors"
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestPoint_Tile(t *testing.T) {
+	tests := []struct {
+		name string
+		p    *Point
+		zoom int
+		want Tile
+	}{
+		{
+			name: "TestPoint_Tile 1",
+			p:    NewPoint(121.506377, 31.245105, "东方明珠"),
+			zoom: 16,
+			want: Tile{X: 54887, Y: 26775, Z: 16},
+		},
+		{
+			name: "TestPoint_Tile 2",
+			p:    NewPoint(13.361389, 38.115556, "Palermo"),
+			zoom: 10,
+			want: Tile{X: 550, Y: 394, Z: 10},
+		},
+		{
+			name: "TestPoint_Tile 3",
+			p:    NewPoint(-180, 89, nil),
+			zoom: 3,
+			want: Tile{X: 0, Y: 0, Z: 3},
+		},
+		{
+			name: "TestPoint_Tile 4",
+			p:    NewPoint(180, -90, nil),
+			zoom: 3,
+			want: Tile{X: 7, Y: 7, Z: 3},
+		},
+		{
+			name: "TestPoint_Tile 5",
+			p:    NewPoint(0, 0, nil),
+			zoom: 0,
+			want: Tile{X: 0, Y: 0, Z: 0},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.p.Tile(tt.zoom); got != tt.want {
+				t.Errorf("Tile() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTile_Bounds(t *testing.T) {
+	tests := []struct {
+		name string
+		tile Tile
+		want Rect
+	}{
+		{
+			name: "TestTile_Bounds 1",
+			tile: Tile{X: 0, Y: 0, Z: 0},
+			want: Rect{MinLng: -180, MinLat: -85.0511287798066, MaxLng: 180, MaxLat: 85.0511287798066},
+		},
+		{
+			name: "TestTile_Bounds 2",
+			tile: Tile{X: 1, Y: 0, Z: 1},
+			want: Rect{MinLng: 0, MinLat: 0, MaxLng: 180, MaxLat: 85.0511287798066},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.tile.Bounds()
+			if math.Abs(got.MinLng-tt.want.MinLng) > 1e-9 || math.Abs(got.MinLat-tt.want.MinLat) > 1e-9 ||
+				math.Abs(got.MaxLng-tt.want.MaxLng) > 1e-9 || math.Abs(got.MaxLat-tt.want.MaxLat) > 1e-9 {
+				t.Errorf("Bounds() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTile_Quadkey(t *testing.T) {
+	tests := []struct {
+		name    string
+		tile    Tile
+		quadkey string
+	}{
+		{
+			name:    "TestTile_Quadkey 1",
+			tile:    Tile{X: 3, Y: 5, Z: 3},
+			quadkey: "213",
+		},
+		{
+			name:    "TestTile_Quadkey 2",
+			tile:    Tile{X: 0, Y: 0, Z: 0},
+			quadkey: "",
+		},
+		{
+			name:    "TestTile_Quadkey 3",
+			tile:    Tile{X: 54887, Y: 26775, Z: 16},
+			quadkey: "1321211021120333",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.tile.Quadkey(); got != tt.quadkey {
+				t.Errorf("Quadkey() = %v, want %v", got, tt.quadkey)
+			}
+			if got, err := ParseQuadkey(tt.quadkey); err != nil || got != tt.tile {
+				t.Errorf("ParseQuadkey() = %v, %v, want %v", got, err, tt.tile)
+			}
+		})
+	}
+}
+
+func TestParseQuadkey_invalid(t *testing.T) {
+	for _, quadkey := range []string{"214", "21a", "0000000000000000000000000000000"} {
+		t.Run("TestParseQuadkey_invalid "+quadkey, func(t *testing.T) {
+			if _, err := ParseQuadkey(quadkey); !errors.Is(err, ErrInvalidTile) {
+				t.Errorf("ParseQuadkey() error = %v, want %v", err, ErrInvalidTile)
+			}
+		})
+	}
+}
+
+func TestTile_Geohashes(t *testing.T) {
+	t.Run("TestTile_Geohashes", func(t *testing.T) {
+		want := []Geohash{"S", "T", "W", "X", "U", "V", "Y", "Z"}
+		if got := (Tile{X: 1, Y: 0, Z: 1}).Geohashes(1); !reflect.DeepEqual(got, want) {
+			t.Errorf("Geohashes() = %v, want %v", got, want)
+		}
+		if tile, ok := Geohash("WTW3SZYP").Tile(16); !ok || tile != (Tile{X: 54887, Y: 26775, Z: 16}) {
+			t.Errorf("Geohash.Tile() = %v, %v, want %v", tile, ok, Tile{X: 54887, Y: 26775, Z: 16})
+		}
+	})
+}
+
+func TestTrie_GetPointsByTile(t1 *testing.T) {
+	center := NewPoint(121.506377, 31.245105, "东方明珠")
+	t, points := randomTrie(center, 5000, 0.2)
+	for _, zoom := range []int{0, 8, 12, 14} {
+		tile := center.Tile(zoom)
+		want := map[*Point]struct{}{}
+		for _, p := range points {
+			if p.Tile(zoom) == tile {
+				want[p] = struct{}{}
+			}
+		}
+		t1.Run("TestTrie_GetPointsByTile "+tile.String(), func(t1 *testing.T) {
+			got, err := t.GetPointsByTile(tile)
+			if err != nil {
+				t1.Fatalf("GetPointsByTile() error = %v", err)
+			}
+			assertSamePoints(t1, "GetPointsByTile()", got, want)
+		})
+	}
+	t1.Run("TestTrie_GetPointsByTile invalid", func(t1 *testing.T) {
+		if _, err := t.GetPointsByTile(Tile{X: 2, Y: 0, Z: 1}); !errors.Is(err, ErrInvalidTile) {
+			t1.Errorf("GetPointsByTile() error = %v, want %v", err, ErrInvalidTile)
+		}
+	})
+}
@@ -1,32 +1,47 @@
 package geohash
 
 import (
-	"errors"
 	"sync"
+	"time"
 )
 
 type (
 	// Trie is a geohash coding prefix tree with a height fixed to geohashLen + 1.
 	// Leaf nodes store longitude and latitude data, and non-leaf nodes store geohash coded indexes.
 	Trie struct {
-		root *node
+		root     *node
+		codec    PayloadCodec         // encodes Point.Val for WriteTo and ReadFrom
+		metric   Metric               // measures the distances of the queries
+		datum    Datum                // the datum of the points, see WithDatum
+		expiring map[Geohash]struct{} // the boxes holding points put with a TTL
+		expiry   int64                // the unix nano deadline no point in expiring expires before
+		watchers map[*Watcher]struct{}
 
 		sync.RWMutex
 	}
 
+	// TrieOption configures a Trie created by NewTrie.
+	TrieOption func(*Trie)
+
 	node struct {
-		children  [32]*node // base32
-		passCount uint32    // the number of Box pass the node (leafNode.passCount = 0)
+		children   [32]*node // base32
+		passCount  uint32    // the number of Box pass the node (leafNode.passCount = 0)
+		pointCount uint32    // the number of points under the node, including the expired ones not removed yet
 
 		isLeaf bool
 		*Box   // only belongs to leaf node
 	}
 )
 
-func NewTrie() *Trie {
-	return &Trie{root: &node{}}
+func NewTrie(opts ...TrieOption) *Trie {
+	t := &Trie{root: &node{}}
+	for _, opt := range opts {
+		opt(t)
+	}
+	return t
 }
 
+// Get returns the Box of geohash, a Box whose points have all expired is absent.
 func (t *Trie) Get(geohash Geohash) (*Box, bool) {
 	if t == nil || t.root == nil || !geohash.valid() {
 		return nil, false
@@ -35,13 +50,26 @@ func (t *Trie) Get(geohash Geohash) (*Box, bool) {
 	t.RLock()
 	defer t.RUnlock()
 
-	n := t.search(string(geohash))
-	if n == nil || !n.isLeaf {
+	box, ok := t.root.get(geohash)
+	if !ok || !box.alive(time.Now().UnixNano()) {
 		return nil, false
 	}
-	return n.Box, true
+	return box, true
+}
+
+// Lookup is Get returning ErrInvalidGeohash for an invalid geohash and ErrNotFound for an absent box.
+func (t *Trie) Lookup(geohash Geohash) (*Box, error) {
+	if !geohash.valid() {
+		return nil, ErrInvalidGeohash
+	}
+	box, ok := t.Get(geohash)
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return box, nil
 }
 
+// GetByPrefix returns the Boxes under the geohash prefix except the ones whose points have all expired.
 func (t *Trie) GetByPrefix(prefix string) []*Box {
 	if t == nil || t.root == nil || len(prefix) == 0 {
 		return nil
@@ -50,119 +78,295 @@ func (t *Trie) GetByPrefix(prefix string) []*Box {
 	t.RLock()
 	defer t.RUnlock()
 
-	n := t.search(prefix)
-	if n == nil {
+	boxes := t.root.getByPrefix(prefix)
+	if len(t.expiring) == 0 {
+		return boxes
+	}
+
+	now := time.Now().UnixNano()
+	res := boxes[:0]
+	for _, box := range boxes {
+		if box.alive(now) {
+			res = append(res, box)
+		}
+	}
+	if len(res) == 0 {
 		return nil
 	}
-	if n.isLeaf {
-		return []*Box{n.Box}
+	return res
+}
+
+// Put puts the point into the Trie, it returns a *PointError for an invalid point, see Point.Validate,
+// including one the conversion into the datum of the Trie moves out of range.
+func (t *Trie) Put(point *Point) error {
+	if t == nil || t.root == nil {
+		return ErrNilTrie
+	}
+	point, err := t.normalizeValid(point)
+	if err != nil {
+		return err
 	}
 
-	return n.dfs()
+	t.Lock()
+	defer t.Unlock()
+
+	t.putWithDeadline(point, 0)
+	return nil
 }
 
-func (t *Trie) Put(point *Point) {
-	if t == nil || t.root == nil || point == nil {
-		return
+func (t *Trie) Delete(geohash Geohash) bool {
+	if t == nil || t.root == nil || !geohash.valid() {
+		return false
 	}
 
-	geohash := point.Geohash()
+	t.Lock()
+	defer t.Unlock()
+
+	box, ok := t.root.get(geohash)
+	if !ok || !t.root.delete(geohash) {
+		return false
+	}
+	t.forget(geohash)
+	if len(t.watchers) > 0 {
+		for _, point := range box.PointSet {
+			t.notify(point, nil)
+		}
+	}
+	return true
+}
+
+// Remove is Delete returning ErrInvalidGeohash for an invalid geohash and ErrNotFound for an absent box.
+func (t *Trie) Remove(geohash Geohash) error {
+	if !geohash.valid() {
+		return ErrInvalidGeohash
+	}
+	if !t.Delete(geohash) {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// Move replaces the point from with the point to, it returns false if from is not in the Trie.
+// The point to expires when from would have, see PutWithTTL.
+func (t *Trie) Move(from, to *Point) bool {
+	if t == nil || t.root == nil || from == nil {
+		return false
+	}
+	to, err := t.normalizeValid(to)
+	if err != nil {
+		return false
+	}
+	from = t.normalize(from)
 
 	t.Lock()
 	defer t.Unlock()
 
-	if n := t.search(string(geohash)); n != nil && n.isLeaf {
-		n.add(point)
-		return
+	old, deadline := t.root.lookup(from), t.root.deadline(from)
+	if !t.root.remove(from) {
+		return false
+	}
+	t.forget(from.Geohash())
+	if replaced := t.root.lookup(to); replaced != nil {
+		t.notify(old, nil)
+		old = replaced
+	}
+	t.expire(t.root.put(to), to, deadline)
+	t.notify(old, to)
+	return true
+}
+
+// GetPointsByCircle returns the points within radius meters of center, measured by the Metric of the Trie.
+// It returns an ErrInvalidPoint for an invalid center and an ErrInvalidRadius for a radius of 0.
+func (t *Trie) GetPointsByCircle(center *Point, radius uint32) ([]*Point, error) {
+	if t == nil || t.root == nil {
+		return nil, ErrNilTrie
+	}
+	if err := center.Validate(); err != nil {
+		return nil, err
+	}
+	if radius == 0 {
+		return nil, ErrInvalidRadius
+	}
+	center = t.normalize(center)
+
+	t.RLock()
+	defer t.RUnlock()
+
+	return t.collect(newCircleRegion(center, Distance(radius), t.distanceMetric())), nil
+}
+
+// Count returns the number of boxes, see CountPoints for the number of points.
+func (t *Trie) Count() uint32 {
+	if t == nil || t.root == nil {
+		return 0
 	}
 
-	move := t.root
+	t.RLock()
+	defer t.RUnlock()
+
+	return t.root.passCount
+}
+
+// CountPoints returns the number of points in O(1).
+func (t *Trie) CountPoints() uint32 {
+	if t == nil || t.root == nil {
+		return 0
+	}
+
+	t.RLock()
+	defer t.RUnlock()
+
+	return t.root.pointCount
+}
+
+// CountByPrefix returns the number of points under the geohash prefix in O(len(prefix)).
+func (t *Trie) CountByPrefix(prefix string) uint32 {
+	if t == nil || t.root == nil || len(prefix) == 0 {
+		return 0
+	}
+
+	t.RLock()
+	defer t.RUnlock()
+
+	return t.root.countByPrefix(prefix)
+}
+
+func (t *Trie) search(prefix string) *node {
+	if t == nil {
+		return nil
+	}
+	return t.root.search(prefix)
+}
+
+// The node methods below never lock, the caller must hold the Trie lock.
+
+func (n *node) get(geohash Geohash) (*Box, bool) {
+	leaf := n.search(string(geohash))
+	if leaf == nil || !leaf.isLeaf {
+		return nil, false
+	}
+	return leaf.Box, true
+}
+
+func (n *node) getByPrefix(prefix string) []*Box {
+	move := n.search(prefix)
+	if move == nil {
+		return nil
+	}
+	if move.isLeaf {
+		return []*Box{move.Box}
+	}
+
+	return move.dfs()
+}
+
+// put puts the point and returns the Box it belongs to
+func (n *node) put(point *Point) *Box {
+	geohash := point.Geohash()
+	leaf := n.search(string(geohash))
+	isNewBox := leaf == nil || !leaf.isLeaf
+	isNewPoint := isNewBox || leaf.PointSet[point.key()] == nil
+
+	move := n
 	for i := 0; i < geohashLen; i++ {
 		childIndex := decode(geohash[i])
 		if move.children[childIndex] == nil {
 			move.children[childIndex] = &node{}
 		}
-		move.passCount++
+		if isNewBox {
+			move.passCount++
+		}
+		if isNewPoint {
+			move.pointCount++
+		}
 		move = move.children[childIndex]
 	}
+	if isNewPoint {
+		move.pointCount++
+	}
+
+	if !isNewBox {
+		move.add(point)
+		return move.Box
+	}
 	move.isLeaf = true
 	move.Box = NewBox(geohash, map[string]*Point{point.key(): point})
+	return move.Box
 }
 
-func (t *Trie) Delete(geohash Geohash) bool {
-	if t == nil || t.root == nil || !geohash.valid() {
-		return false
-	}
-
-	t.Lock()
-	defer t.Unlock()
-
-	n := t.search(string(geohash))
-	if n == nil || !n.isLeaf {
+// delete removes the leaf of geohash, and prunes the ancestors no Box passes any more
+func (n *node) delete(geohash Geohash) bool {
+	leaf := n.search(string(geohash))
+	if leaf == nil || !leaf.isLeaf {
 		return false
 	}
 
-	move := t.root
+	move := n
 	for i := 0; i < geohashLen; i++ {
 		index := decode(geohash[i])
+		child := move.children[index]
 		move.passCount--
-		if move.passCount == 0 {
+		move.pointCount -= leaf.pointCount
+		if child.isLeaf || child.passCount == 1 {
 			move.children[index] = nil
 			return true
 		}
-		move = move.children[index]
+		move = child
 	}
 
 	return false
 }
 
-func (t *Trie) GetPointsByCircle(center *Point, radius uint32) ([]*Point, error) {
-	if t == nil || t.root == nil || center == nil || radius == 0 {
-		return nil, errors.New("invalid param")
+// lookup returns the point stored with the same key as point, nil if there is none
+func (n *node) lookup(point *Point) *Point {
+	leaf := n.search(string(point.Geohash()))
+	if leaf == nil || !leaf.isLeaf {
+		return nil
 	}
+	return leaf.PointSet[point.key()]
+}
 
-	l, err := getGeohashLenByDiameter(radius << 1)
-	if err != nil {
-		return nil, err
+func (n *node) countByPrefix(prefix string) uint32 {
+	move := n.search(prefix)
+	if move == nil {
+		return 0
 	}
+	return move.pointCount
+}
 
-	points := center.circumscribedSquarePointsByCircle(radius)
-
-	t.RLock()
-	defer t.RUnlock()
-
-	res := make([]*Point, 0)
-	duplicateBox := map[Geohash]struct{}{}
-	for _, p := range points {
-		for _, box := range t.GetByPrefix(string(p.Geohash()[:l])) {
-			if _, ok := duplicateBox[box.GetGeohash()]; !ok {
-				for _, v := range box.GetAllPoints() {
-					if center.Distance(v) <= radius {
-						res = append(res, v)
-					}
-				}
-				duplicateBox[box.GetGeohash()] = struct{}{}
-			}
-		}
+// remove removes the point from its box, and deletes the box once it is empty
+func (n *node) remove(point *Point) bool {
+	geohash := point.Geohash()
+	leaf := n.search(string(geohash))
+	if leaf == nil || !leaf.isLeaf {
+		return false
 	}
 
-	return res, nil
-}
-
-func (t *Trie) Count() uint32 {
-	if t == nil || t.root == nil {
-		return 0
+	key := point.key()
+	if _, ok := leaf.PointSet[key]; !ok {
+		return false
+	}
+	if leaf.pointCount == 1 {
+		leaf.Box.remove(key)
+		return n.delete(geohash)
 	}
 
-	return t.root.passCount
+	move := n
+	for i := 0; i < geohashLen; i++ {
+		move.pointCount--
+		move = move.children[decode(geohash[i])]
+	}
+	move.pointCount--
+	move.Box.remove(key)
+	return true
 }
 
-func (t *Trie) search(prefix string) *node {
-	if t == nil || t.root == nil || len(prefix) == 0 {
+func (n *node) search(prefix string) *node {
+	if n == nil || len(prefix) == 0 {
 		return nil
 	}
 
-	move := t.root
+	move := n
 	for i := 0; i < len(prefix); i++ {
 		childIndex := decode(prefix[i])
 		if childIndex == invalidCode || move.children[childIndex] == nil {
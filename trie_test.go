@@ -1,8 +1,12 @@
 package geohash
 
 import (
+	"errors"
+	"math"
 	"reflect"
+	"sync"
 	"testing"
+	"time"
 )
 
 func TestNewTrie(t *testing.T) {
@@ -67,6 +71,117 @@ func TestTrie_Put(t1 *testing.T) {
 	t.Put(p2)
 }
 
+func TestTrie_Put_invalid(t1 *testing.T) {
+	t := NewTrie()
+	for _, p := range []*Point{NewPoint(math.NaN(), 0, nil), NewPoint(540, 0, nil), NewPoint(0, 200, nil), NewPoint(0, math.Inf(-1), nil)} {
+		t1.Run("TestTrie_Put_invalid", func(t1 *testing.T) {
+			var pointErr *PointError
+			if err := t.Put(p); !errors.As(err, &pointErr) {
+				t1.Errorf("Put() error = %v, want a *PointError", err)
+			}
+			if err := t.PutWithTTL(p, time.Minute); !errors.As(err, &pointErr) {
+				t1.Errorf("PutWithTTL() error = %v, want a *PointError", err)
+			}
+		})
+	}
+	if got := t.CountPoints(); got != 0 {
+		t1.Errorf("CountPoints() = %v, want %v", got, 0)
+	}
+}
+
+func TestTrie_nil(t1 *testing.T) {
+	p := NewPoint(121.506377, 31.245105, "东方明珠")
+	var (
+		t *Trie
+		s *ShardedTrie
+		e *GeofenceEngine
+	)
+	t1.Run("TestTrie_nil", func(t1 *testing.T) {
+		if err := t.Put(p); !errors.Is(err, ErrNilTrie) {
+			t1.Errorf("Put() error = %v, want %v", err, ErrNilTrie)
+		}
+		if _, err := (&Trie{}).GetPointsByCircle(p, 10); !errors.Is(err, ErrNilTrie) {
+			t1.Errorf("GetPointsByCircle() error = %v, want %v", err, ErrNilTrie)
+		}
+		if err := s.Put(p); !errors.Is(err, ErrNilTrie) {
+			t1.Errorf("ShardedTrie.Put() error = %v, want %v", err, ErrNilTrie)
+		}
+		if _, err := (&Snapshot{}).GetPointsByCircle(p, 10); !errors.Is(err, ErrNilTrie) {
+			t1.Errorf("Snapshot.GetPointsByCircle() error = %v, want %v", err, ErrNilTrie)
+		}
+		if err := e.AddCircleFence("fence", p, 10); !errors.Is(err, ErrNilTrie) {
+			t1.Errorf("AddCircleFence() error = %v, want %v", err, ErrNilTrie)
+		}
+	})
+}
+
+func TestTrie_Lookup(t1 *testing.T) {
+	t := NewTrie()
+	t.Put(NewPoint(121.506377, 31.245105, "东方明珠"))
+	tests := []struct {
+		name    string
+		geohash Geohash
+		wantErr error
+	}{
+		{
+			name:    "TestTrie_Lookup 1",
+			geohash: "WTW3SZYP",
+			wantErr: nil,
+		},
+		{
+			name:    "TestTrie_Lookup 2",
+			geohash: "WTW3SZYQ",
+			wantErr: ErrNotFound,
+		},
+		{
+			name:    "TestTrie_Lookup 3",
+			geohash: "WTW3",
+			wantErr: ErrInvalidGeohash,
+		},
+	}
+	for _, tt := range tests {
+		t1.Run(tt.name, func(t1 *testing.T) {
+			box, err := t.Lookup(tt.geohash)
+			if !errors.Is(err, tt.wantErr) || (box == nil) == (err == nil) {
+				t1.Errorf("Lookup() = %v, %v, want error %v", box, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestTrie_Remove(t1 *testing.T) {
+	t := NewTrie()
+	t.Put(NewPoint(121.506377, 31.245105, "东方明珠"))
+	tests := []struct {
+		name    string
+		geohash Geohash
+		wantErr error
+	}{
+		{
+			name:    "TestTrie_Remove 1",
+			geohash: "WTW3SZYP",
+			wantErr: nil,
+		},
+		{
+			name:    "TestTrie_Remove 2",
+			geohash: "WTW3SZYP",
+			wantErr: ErrNotFound,
+		},
+		{
+			name:    "TestTrie_Remove 3",
+			geohash: "",
+			wantErr: ErrInvalidGeohash,
+		},
+	}
+	for _, tt := range tests {
+		t1.Run(tt.name, func(t1 *testing.T) {
+			if err := t.Remove(tt.geohash); !errors.Is(err, tt.wantErr) {
+				t1.Errorf("Remove() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
 func TestTrie_Delete(t1 *testing.T) {
 	t := NewTrie()
 	p1 := NewPoint(13.361389, 38.115556, "Palermo")
@@ -84,6 +199,52 @@ func TestTrie_Delete(t1 *testing.T) {
 			t1.Errorf("Delete() = %v, want %v", got, false)
 		}
 	})
+	t1.Run("TestTrie_Delete sibling", func(t1 *testing.T) {
+		t.Put(NewPoint(121.506377, 31.245105, "东方明珠"))
+		t.Put(NewPoint(121.506577, 31.245105, "东方明珠东"))
+		if got := t.Count(); got != 2 {
+			t1.Errorf("Count() = %v, want %v", got, 2)
+		}
+		if got := t.Delete("WTW3SZYP"); got != true {
+			t1.Errorf("Delete() = %v, want %v", got, true)
+		}
+		if _, got := t.Get("WTW3SZYP"); got != false {
+			t1.Errorf("Get() = %v, want %v", got, false)
+		}
+		if got := t.Count(); got != 1 {
+			t1.Errorf("Count() = %v, want %v", got, 1)
+		}
+	})
+}
+
+func TestTrie_Move(t1 *testing.T) {
+	t := NewTrie()
+	p1 := NewPoint(121.506377, 31.245105, "东方明珠")
+	p2 := NewPoint(121.506378, 31.245105, "东方明珠")
+	t.Put(p1)
+	t.Put(p2)
+	t1.Run("TestTrie_Move", func(t1 *testing.T) {
+		p3 := NewPoint(121.4871639, 31.2388556, "东方明珠")
+		if got := t.Move(p1, p3); got != true {
+			t1.Errorf("Move() = %v, want %v", got, true)
+		}
+		if got := t.Move(p1, p3); got != false {
+			t1.Errorf("Move() = %v, want %v", got, false)
+		}
+		want := NewBox(p2.Geohash(), map[string]*Point{p2.key(): p2})
+		if got, _ := t.Get(p2.Geohash()); !reflect.DeepEqual(got, want) {
+			t1.Errorf("Get() = %v, want %v", got, want)
+		}
+		if got := t.Move(p2, p3); got != true {
+			t1.Errorf("Move() = %v, want %v", got, true)
+		}
+		if _, got := t.Get(p2.Geohash()); got != false {
+			t1.Errorf("Get() = %v, want %v", got, false)
+		}
+		if got := t.Count(); got != 1 {
+			t1.Errorf("Count() = %v, want %v", got, 1)
+		}
+	})
 }
 
 func TestTrie_GetPointsByCircle(t1 *testing.T) {
@@ -124,6 +285,112 @@ func TestTrie_GetPointsByCircle(t1 *testing.T) {
 	})
 }
 
+func TestTrie_GetPointsByCircle_errors(t1 *testing.T) {
+	t := NewTrie()
+	center := NewPoint(121.506377, 31.245105, "东方明珠")
+	tests := []struct {
+		name    string
+		center  *Point
+		radius  uint32
+		wantErr error
+	}{
+		{
+			name:    "TestTrie_GetPointsByCircle_errors 1",
+			center:  nil,
+			radius:  1000,
+			wantErr: ErrInvalidPoint,
+		},
+		{
+			name:    "TestTrie_GetPointsByCircle_errors 2",
+			center:  NewPoint(0, 100, nil),
+			radius:  1000,
+			wantErr: ErrInvalidPoint,
+		},
+		{
+			name:    "TestTrie_GetPointsByCircle_errors 3",
+			center:  center,
+			radius:  0,
+			wantErr: ErrInvalidRadius,
+		},
+	}
+	for _, tt := range tests {
+		t1.Run(tt.name, func(t1 *testing.T) {
+			if _, err := t.GetPointsByCircle(tt.center, tt.radius); !errors.Is(err, tt.wantErr) {
+				t1.Errorf("GetPointsByCircle() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestTrie_GetPointsByCircle_metric(t1 *testing.T) {
+	center := NewPoint(121.506377, 31.245105, "东方明珠")
+	near := NewPoint(121.506377, 31.245105+100.5/metersPerDegree, "100.5m")
+	far := NewPoint(121.506377, 32.245105, "1°")
+	for _, t := range []*Trie{NewTrie(), NewTrie(WithDistanceModel(Spherical))} {
+		t.Put(near)
+		t.Put(far)
+		// the default is the same as Spherical: any radius, compared without truncating the distances
+		t1.Run("TestTrie_GetPointsByCircle_metric", func(t1 *testing.T) {
+			for _, tt := range []struct {
+				radius uint32
+				want   []*Point
+			}{
+				{radius: 100, want: []*Point{}},
+				{radius: 101, want: []*Point{near}},
+				{radius: 200000, want: []*Point{near, far}},
+			} {
+				got, err := t.GetPointsByCircle(center, tt.radius)
+				if err != nil || !reflect.DeepEqual(got, tt.want) {
+					t1.Errorf("GetPointsByCircle(%v) = %v, error = %v, want %v", tt.radius, got, err, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestTrie_CountPoints(t1 *testing.T) {
+	t := NewTrie()
+	p1 := NewPoint(13.361389, 38.115556, "Palermo")
+	p2 := NewPoint(121.506377, 31.245105, "东方明珠")
+	p3 := NewPoint(121.506378, 31.245105, "东方明珠")
+	p4 := NewPoint(121.4871639, 31.2388556, "上海和平饭店")
+	t.Put(p1)
+	t.Put(p2)
+	t.Put(p3)
+	t.Put(p3)
+	t.Put(p4)
+	t1.Run("TestTrie_CountPoints", func(t1 *testing.T) {
+		if got := t.Count(); got != 3 {
+			t1.Errorf("Count() = %v, want %v", got, 3)
+		}
+		if got := t.CountPoints(); got != 4 {
+			t1.Errorf("CountPoints() = %v, want %v", got, 4)
+		}
+		if got := t.CountByPrefix("WTW3S"); got != 3 {
+			t1.Errorf("CountByPrefix() = %v, want %v", got, 3)
+		}
+		if got := t.CountByPrefix(string(p2.Geohash())); got != 2 {
+			t1.Errorf("CountByPrefix() = %v, want %v", got, 2)
+		}
+		if got := t.CountByPrefix("B"); got != 0 {
+			t1.Errorf("CountByPrefix() = %v, want %v", got, 0)
+		}
+	})
+	t1.Run("TestTrie_CountPoints after writes", func(t1 *testing.T) {
+		t.Move(p3, NewPoint(13.361389, 38.115557, "Palermo"))
+		t.Delete(p4.Geohash())
+		if got := t.CountPoints(); got != 3 {
+			t1.Errorf("CountPoints() = %v, want %v", got, 3)
+		}
+		if got := t.CountByPrefix("S"); got != 2 {
+			t1.Errorf("CountByPrefix() = %v, want %v", got, 2)
+		}
+		if got := t.CountByPrefix("W"); got != 1 {
+			t1.Errorf("CountByPrefix() = %v, want %v", got, 1)
+		}
+	})
+}
+
 func TestTrie_search(t1 *testing.T) {
 	t := NewTrie()
 	p1 := NewPoint(13.361389, 38.115556, "Palermo")
@@ -137,10 +404,11 @@ func TestTrie_search(t1 *testing.T) {
 		}
 		got1 := t.search("SQC8B49R")
 		if !reflect.DeepEqual(got1, &node{
-			children:  [32]*node{},
-			passCount: 0,
-			isLeaf:    true,
-			Box:       NewBox("SQC8B49R", map[string]*Point{p1.key(): p1}),
+			children:   [32]*node{},
+			passCount:  0,
+			pointCount: 1,
+			isLeaf:     true,
+			Box:        NewBox("SQC8B49R", map[string]*Point{p1.key(): p1}),
 		}) {
 			t1.Errorf("Get() got1 = %v, want %v", got, p1)
 		}
@@ -164,3 +432,49 @@ func Test_node_dfs(t1 *testing.T) {
 		}
 	})
 }
+
+// TestTrie_concurrency mixes writers and compound readers on one Trie,
+// a recursive read lock would deadlock as soon as a writer queues between the two RLock calls.
+func TestTrie_concurrency(t1 *testing.T) {
+	t := NewTrie()
+	center := NewPoint(121.506377, 31.245105, "东方明珠")
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+
+		var wg sync.WaitGroup
+		for w := 0; w < 8; w++ {
+			wg.Add(2)
+			go func(w int) {
+				defer wg.Done()
+				for i := 0; i < 500; i++ {
+					p := NewPoint(center.Lng+float64(w*500+i)/1e5, center.Lat, i)
+					t.Put(p)
+					if i%3 == 0 {
+						t.Delete(p.Geohash())
+					}
+				}
+			}(w)
+			go func() {
+				defer wg.Done()
+				for i := 0; i < 500; i++ {
+					if _, err := t.GetPointsByCircle(center, 5000); err != nil {
+						t1.Errorf("GetPointsByCircle() error = %v", err)
+						return
+					}
+					t.GetByPrefix("WTW")
+					t.Get(center.Geohash())
+					t.Count()
+				}
+			}()
+		}
+		wg.Wait()
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(30 * time.Second):
+		t1.Fatal("deadlock under mixed load")
+	}
+}
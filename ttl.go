@@ -0,0 +1,161 @@
+package geohash
+
+import (
+	"sync"
+	"time"
+)
+
+const defaultReaperInterval = time.Second
+
+// PutWithTTL puts the point which expires after ttl, a ttl <= 0 never expires.
+// Expired points are skipped by the queries, Get, GetByPrefix, Box.GetPointSet and Box.GetAllPoints,
+// until RemoveExpired or the reaper started by StartReaper removes them.
+// Putting the same point again without a TTL makes it permanent.
+func (t *Trie) PutWithTTL(point *Point, ttl time.Duration) error {
+	if t == nil || t.root == nil {
+		return ErrNilTrie
+	}
+	point, err := t.normalizeValid(point)
+	if err != nil {
+		return err
+	}
+
+	t.putUntil(point, deadlineOf(ttl))
+	return nil
+}
+
+// RemoveExpired removes the expired points and prunes the nodes left empty,
+// it returns the number of points removed.
+func (t *Trie) RemoveExpired() int {
+	if t == nil || t.root == nil {
+		return 0
+	}
+
+	t.Lock()
+	defer t.Unlock()
+
+	return t.removeExpired(time.Now().UnixNano())
+}
+
+// StartReaper removes the expired points every interval in the background until stop is called,
+// an interval <= 0 defaults to a second.
+func (t *Trie) StartReaper(interval time.Duration) (stop func()) {
+	if interval <= 0 {
+		interval = defaultReaperInterval
+	}
+
+	done := make(chan struct{})
+	exited := make(chan struct{})
+	go func() {
+		defer close(exited)
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				t.RemoveExpired()
+			}
+		}
+	}()
+
+	once := sync.Once{}
+	return func() {
+		once.Do(func() {
+			close(done)
+			<-exited
+		})
+	}
+}
+
+// deadlineOf returns the unix nano deadline of ttl, 0 if it never expires
+func deadlineOf(ttl time.Duration) int64 {
+	if ttl <= 0 {
+		return 0
+	}
+	return time.Now().Add(ttl).UnixNano()
+}
+
+func (t *Trie) putUntil(point *Point, deadline int64) {
+	t.Lock()
+	defer t.Unlock()
+
+	t.putWithDeadline(point, deadline)
+}
+
+// putWithDeadline puts the point expiring at the unix nano deadline, 0 means never
+func (t *Trie) putWithDeadline(point *Point, deadline int64) {
+	old := t.root.lookup(point)
+	box := t.root.put(point)
+	t.expire(box, point, deadline)
+	t.notify(old, point)
+}
+
+// expire makes the point in box expire at the unix nano deadline, 0 means never
+func (t *Trie) expire(box *Box, point *Point, deadline int64) {
+	if deadline <= 0 {
+		t.forget(box.Geohash)
+		return
+	}
+
+	box.expire(point.key(), deadline)
+	if len(t.expiring) == 0 || deadline < t.expiry {
+		t.expiry = deadline
+	}
+	if t.expiring == nil {
+		t.expiring = map[Geohash]struct{}{}
+	}
+	t.expiring[box.Geohash] = struct{}{}
+}
+
+// forget drops the box of geohash from the expiring boxes unless it still holds a point put with a TTL
+func (t *Trie) forget(geohash Geohash) {
+	if _, ok := t.expiring[geohash]; !ok {
+		return
+	}
+	if box, ok := t.root.get(geohash); ok && len(box.expireAt) > 0 {
+		return
+	}
+	delete(t.expiring, geohash)
+}
+
+// deadline returns the unix nano deadline of the point stored with the same key as point, 0 if it never expires
+func (n *node) deadline(point *Point) int64 {
+	leaf := n.search(string(point.Geohash()))
+	if leaf == nil || !leaf.isLeaf {
+		return 0
+	}
+	return leaf.expireAt[point.key()]
+}
+
+func (t *Trie) removeExpired(now int64) int {
+	var count int
+	t.expiry = 0
+	for geohash := range t.expiring {
+		box, ok := t.root.get(geohash)
+		if !ok {
+			delete(t.expiring, geohash)
+			continue
+		}
+
+		for key, deadline := range box.expireAt {
+			if deadline > now {
+				if t.expiry == 0 || deadline < t.expiry {
+					t.expiry = deadline
+				}
+				continue
+			}
+			point := box.PointSet[key]
+			if t.root.remove(point) {
+				t.notify(point, nil)
+				count++
+			}
+		}
+		if len(box.expireAt) == 0 {
+			delete(t.expiring, geohash)
+		}
+	}
+	return count
+}
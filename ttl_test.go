@@ -0,0 +1,181 @@
+package geohash
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestTrie_PutWithTTL(t1 *testing.T) {
+	t := NewTrie()
+	p1 := NewPoint(121.506377, 31.245105, "东方明珠")
+	p2 := NewPoint(121.506378, 31.245105, "东方明珠")
+	t.PutWithTTL(p1, time.Nanosecond)
+	t.PutWithTTL(p2, time.Hour)
+	time.Sleep(time.Millisecond)
+	t1.Run("TestTrie_PutWithTTL", func(t1 *testing.T) {
+		got, err := t.GetPointsByCircle(p1, 10)
+		if err != nil {
+			t1.Fatalf("GetPointsByCircle() error = %v", err)
+		}
+		if !reflect.DeepEqual(got, []*Point{p2}) {
+			t1.Errorf("GetPointsByCircle() got = %v, want %v", got, []*Point{p2})
+		}
+	})
+	t1.Run("TestTrie_PutWithTTL permanent", func(t1 *testing.T) {
+		t.Put(p1)
+		got, err := t.GetPointsByCircle(p1, 10)
+		if err != nil {
+			t1.Fatalf("GetPointsByCircle() error = %v", err)
+		}
+		if len(got) != 2 {
+			t1.Errorf("GetPointsByCircle() got = %v, want %v", got, []*Point{p1, p2})
+		}
+	})
+}
+
+func TestTrie_PutWithTTL_Get(t1 *testing.T) {
+	t := NewTrie()
+	p1 := NewPoint(13.361389, 38.115556, "Palermo")
+	p2 := NewPoint(121.506377, 31.245105, "东方明珠")
+	p3 := NewPoint(121.506378, 31.245105, "东方明珠")
+	t.PutWithTTL(p1, time.Nanosecond)
+	t.PutWithTTL(p2, time.Nanosecond)
+	t.PutWithTTL(p3, time.Hour)
+	time.Sleep(time.Millisecond)
+	t1.Run("TestTrie_PutWithTTL_Get", func(t1 *testing.T) {
+		if _, err := t.Lookup(p1.Geohash()); !errors.Is(err, ErrNotFound) {
+			t1.Errorf("Lookup() error = %v, want %v", err, ErrNotFound)
+		}
+		if got := t.GetByPrefix("S"); got != nil {
+			t1.Errorf("GetByPrefix() = %v, want %v", got, nil)
+		}
+		want := map[string]*Point{p3.key(): p3}
+		if got, _ := t.Get(p3.Geohash()); !reflect.DeepEqual(got.GetPointSet(), want) {
+			t1.Errorf("GetPointSet() = %v, want %v", got.GetPointSet(), want)
+		}
+		if got := len(t.GetByPrefix("W")); got != 1 {
+			t1.Errorf("GetByPrefix() = %v boxes, want %v", got, 1)
+		}
+	})
+}
+
+func TestTrie_RemoveExpired(t1 *testing.T) {
+	t := NewTrie()
+	p1 := NewPoint(13.361389, 38.115556, "Palermo")
+	p2 := NewPoint(121.506377, 31.245105, "东方明珠")
+	p3 := NewPoint(121.506378, 31.245105, "东方明珠")
+	t.PutWithTTL(p1, time.Nanosecond)
+	t.PutWithTTL(p2, time.Nanosecond)
+	t.PutWithTTL(p3, time.Hour)
+	time.Sleep(time.Millisecond)
+	t1.Run("TestTrie_RemoveExpired", func(t1 *testing.T) {
+		if got := t.RemoveExpired(); got != 2 {
+			t1.Errorf("RemoveExpired() = %v, want %v", got, 2)
+		}
+		if _, ok := t.Get(p1.Geohash()); ok {
+			t1.Errorf("Get() = %v, want %v", ok, false)
+		}
+		if got := t.GetByPrefix("S"); got != nil {
+			t1.Errorf("GetByPrefix() = %v, want %v", got, nil)
+		}
+		want := map[string]*Point{p3.key(): p3}
+		if got, _ := t.Get(p3.Geohash()); !reflect.DeepEqual(got.GetPointSet(), want) {
+			t1.Errorf("Get() = %v, want %v", got.GetPointSet(), want)
+		}
+		if got := t.Count(); got != 1 {
+			t1.Errorf("Count() = %v, want %v", got, 1)
+		}
+		if got := len(t.expiring); got != 1 {
+			t1.Errorf("expiring = %v, want %v", got, 1)
+		}
+	})
+}
+
+func TestTrie_StartReaper(t1 *testing.T) {
+	t := NewTrie()
+	t.PutWithTTL(NewPoint(13.361389, 38.115556, "Palermo"), time.Millisecond)
+	stop := t.StartReaper(time.Millisecond)
+	defer stop()
+	t1.Run("TestTrie_StartReaper", func(t1 *testing.T) {
+		deadline := time.Now().Add(5 * time.Second)
+		for t.Count() != 0 {
+			if time.Now().After(deadline) {
+				t1.Fatalf("Count() = %v, want %v", t.Count(), 0)
+			}
+			time.Sleep(time.Millisecond)
+		}
+		stop()
+	})
+}
+
+func TestTrie_StartReaper_interval(t1 *testing.T) {
+	t := NewTrie()
+	t.PutWithTTL(NewPoint(13.361389, 38.115556, "Palermo"), time.Millisecond)
+	// an interval of 0 falls back to the default instead of panicking in the reaper
+	stop := t.StartReaper(0)
+	defer stop()
+	t1.Run("TestTrie_StartReaper_interval", func(t1 *testing.T) {
+		deadline := time.Now().Add(5 * time.Second)
+		for t.Count() != 0 {
+			if time.Now().After(deadline) {
+				t1.Fatalf("Count() = %v, want %v", t.Count(), 0)
+			}
+			time.Sleep(time.Millisecond)
+		}
+	})
+}
+
+func TestTrie_Move_TTL(t1 *testing.T) {
+	t := NewTrie()
+	from := NewPoint(121.506377, 31.245105, "东方明珠")
+	to := NewPoint(121.4871639, 31.2388556, "东方明珠")
+	t.PutWithTTL(from, time.Millisecond)
+	t.Move(from, to)
+	time.Sleep(2 * time.Millisecond)
+	t1.Run("TestTrie_Move_TTL", func(t1 *testing.T) {
+		if got := t.RemoveExpired(); got != 1 {
+			t1.Errorf("RemoveExpired() = %v, want %v", got, 1)
+		}
+		if got := t.CountPoints(); got != 0 {
+			t1.Errorf("CountPoints() = %v, want %v", got, 0)
+		}
+	})
+}
+
+func TestTrie_expiring(t1 *testing.T) {
+	p := NewPoint(121.506377, 31.245105, "东方明珠")
+	t1.Run("TestTrie_expiring Delete", func(t1 *testing.T) {
+		t := NewTrie()
+		t.PutWithTTL(p, time.Hour)
+		t.Delete(p.Geohash())
+		if got := len(t.expiring); got != 0 {
+			t1.Errorf("len(expiring) = %v, want %v", got, 0)
+		}
+	})
+	t1.Run("TestTrie_expiring Put", func(t1 *testing.T) {
+		t := NewTrie()
+		t.PutWithTTL(p, time.Hour)
+		t.Put(p)
+		if got := len(t.expiring); got != 0 {
+			t1.Errorf("len(expiring) = %v, want %v", got, 0)
+		}
+	})
+	t1.Run("TestTrie_expiring countable", func(t1 *testing.T) {
+		t := NewTrie()
+		t.PutWithTTL(p, time.Hour)
+		t.PutWithTTL(NewPoint(121.506378, 31.245105, "东方明珠"), time.Minute)
+		within := false
+		t.walk(newCircleRegion(p, 1000, Spherical), func(n *node, w bool) bool {
+			within = within || w
+			return true
+		})
+		if !within {
+			t1.Errorf("walk() within = %v, want %v", within, true)
+		}
+		if got := t.expiry; got != t.root.deadline(NewPoint(121.506378, 31.245105, nil)) {
+			t1.Errorf("expiry = %v, want the deadline of the minute", got)
+		}
+	})
+}
@@ -0,0 +1,229 @@
+package geohash
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+const (
+	utmScale         = 0.9996
+	utmFalseEasting  = 500000
+	utmFalseNorthing = 10000000 // of the southern hemisphere
+	utmMinLat        = -80
+	utmMaxLat        = 84
+
+	mgrsSquare    = 100000 // the side of a 100 km grid square
+	mgrsCycle     = 2000000
+	mgrsMaxDigits = 5
+)
+
+var (
+	utmBands    = "CDEFGHJKLMNPQRSTUVWXX" // 8° bands from 80°S, X is extended to 84°N
+	mgrsColumns = [3]string{"ABCDEFGH", "JKLMNPQR", "STUVWXYZ"}
+	mgrsRows    = "ABCDEFGHJKLMNPQRSTUV"
+)
+
+// UTM is a position in the Universal Transverse Mercator grid on the WGS84 ellipsoid.
+type UTM struct {
+	Zone     int     // from 1 to 60, 6° wide eastward from 180°W
+	Band     byte    // the latitude band from C to X, N and above are in the northern hemisphere
+	Easting  float64 // meters, 500 km at the central meridian of the zone
+	Northing float64 // meters from the equator, plus 10000 km in the southern hemisphere
+}
+
+// UTM converts the point into UTM, it returns an ErrInvalidPoint outside the UTM latitudes from 80°S to 84°N.
+// The zones follow the exceptions of southwest Norway and Svalbard.
+func (p *Point) UTM() (UTM, error) {
+	if err := p.Validate(); err != nil {
+		return UTM{}, err
+	}
+	if p.Lat < utmMinLat || p.Lat > utmMaxLat {
+		return UTM{}, &PointError{Lng: p.Lng, Lat: p.Lat, Reason: "latitude out of UTM range"}
+	}
+
+	zone := utmZone(p.Lng, p.Lat)
+	easting, northing := utmForward(p.Lng, p.Lat, zone)
+	return UTM{Zone: zone, Band: utmBands[int((p.Lat-utmMinLat)/8)], Easting: easting, Northing: northing}, nil
+}
+
+// Point converts the UTM position into a Point.
+func (u UTM) Point() (*Point, error) {
+	if u.Zone < 1 || u.Zone > 60 || strings.IndexByte(utmBands, u.Band) < 0 ||
+		u.Easting <= 0 || u.Easting >= 2*utmFalseEasting || u.Northing < 0 || u.Northing > utmFalseNorthing {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidGridReference, u)
+	}
+
+	lng, lat := utmInverse(u.Easting, u.Northing, u.Zone, u.Band >= 'N')
+	return NewPoint(lng, lat, nil), nil
+}
+
+// String formats the position like "51R 357768 3457727", truncated to meters.
+func (u UTM) String() string {
+	return fmt.Sprintf("%d%c %.0f %.0f", u.Zone, u.Band, math.Floor(u.Easting), math.Floor(u.Northing))
+}
+
+// MGRS converts the point into a Military Grid Reference System string like "51RUQ5776857727",
+// with precision digits for each of the easting and northing in the 100 km square, 5 for a meter and 0 for the square.
+func (p *Point) MGRS(precision int) (string, error) {
+	if precision < 0 || precision > mgrsMaxDigits {
+		return "", fmt.Errorf("%w: precision %d", ErrInvalidGridReference, precision)
+	}
+	u, err := p.UTM()
+	if err != nil {
+		return "", err
+	}
+
+	column := int(u.Easting / mgrsSquare)
+	row := int(u.Northing/mgrsSquare) % len(mgrsRows)
+	if u.Zone%2 == 0 {
+		row = (row + 5) % len(mgrsRows)
+	}
+	unit := math.Pow10(mgrsMaxDigits - precision)
+	easting := int(math.Mod(u.Easting, mgrsSquare) / unit)
+	northing := int(math.Mod(u.Northing, mgrsSquare) / unit)
+
+	res := fmt.Sprintf("%02d%c%c%c", u.Zone, u.Band, mgrsColumns[(u.Zone-1)%3][column-1], mgrsRows[row])
+	if precision > 0 {
+		res += fmt.Sprintf("%0*d%0*d", precision, easting, precision, northing)
+	}
+	return res, nil
+}
+
+// ParseMGRS parses a Military Grid Reference System string, spaces are ignored.
+// It returns the center of the grid square the string denotes, so that a point put into a Trie is at most half a square off.
+func ParseMGRS(mgrs string) (*Point, error) {
+	s := strings.ToUpper(strings.ReplaceAll(mgrs, " ", ""))
+	invalid := fmt.Errorf("%w: %q", ErrInvalidGridReference, mgrs)
+
+	i := 0
+	for i < len(s) && i < 2 && s[i] >= '0' && s[i] <= '9' {
+		i++
+	}
+	zone, err := strconv.Atoi(s[:i])
+	if err != nil || zone < 1 || zone > 60 || len(s) < i+3 {
+		return nil, invalid
+	}
+	band, columnLetter, rowLetter := s[i], s[i+1], s[i+2]
+	digits := s[i+3:]
+	column := strings.IndexByte(mgrsColumns[(zone-1)%3], columnLetter)
+	row := strings.IndexByte(mgrsRows, rowLetter)
+	if strings.IndexByte(utmBands, band) < 0 || column < 0 || row < 0 || len(digits)%2 != 0 || len(digits) > 2*mgrsMaxDigits {
+		return nil, invalid
+	}
+
+	precision := len(digits) / 2
+	unit := math.Pow10(mgrsMaxDigits - precision)
+	var easting, northing float64
+	if precision > 0 {
+		e, errE := strconv.Atoi(digits[:precision])
+		n, errN := strconv.Atoi(digits[precision:])
+		if errE != nil || errN != nil {
+			return nil, invalid
+		}
+		easting, northing = float64(e)*unit, float64(n)*unit
+	}
+
+	if zone%2 == 0 {
+		row = (row + len(mgrsRows) - 5) % len(mgrsRows)
+	}
+	// the row letters repeat every 2000 km, the band tells which cycle the square is in
+	north := band >= 'N'
+	bandLat := float64(utmMinLat + 8*strings.IndexByte(utmBands, band))
+	_, bandNorthing := utmForward(float64(zone*6-183), bandLat, zone)
+	minNorthing := math.Floor(bandNorthing/mgrsSquare) * mgrsSquare
+	squareNorthing := float64(row * mgrsSquare)
+	for squareNorthing < minNorthing {
+		squareNorthing += mgrsCycle
+	}
+
+	u := UTM{
+		Zone:     zone,
+		Band:     band,
+		Easting:  float64((column+1)*mgrsSquare) + easting + unit/2,
+		Northing: squareNorthing + northing + unit/2,
+	}
+	if !north && u.Northing > utmFalseNorthing || north && u.Northing >= utmFalseNorthing {
+		return nil, invalid
+	}
+	return u.Point()
+}
+
+// utmZone returns the zone of the coordinates
+func utmZone(lng, lat float64) int {
+	switch {
+	case lat >= 56 && lat < 64 && lng >= 3 && lng < 12:
+		// southwest Norway
+		return 32
+	case lat >= 72 && lng >= 0 && lng < 42:
+		// Svalbard
+		switch {
+		case lng < 9:
+			return 31
+		case lng < 21:
+			return 33
+		case lng < 33:
+			return 35
+		default:
+			return 37
+		}
+	}
+	return int(math.Min((lng-minLng)/6, 59)) + 1
+}
+
+// utmForward projects the coordinates into the zone by the series of Snyder, Map Projections: A Working Manual
+func utmForward(lng, lat float64, zone int) (easting, northing float64) {
+	e2 := wgs84F * (2 - wgs84F)
+	ep2 := e2 / (1 - e2)
+	phi := lat * math.Pi / 180
+	sinPhi, cosPhi := math.Sincos(phi)
+	tanPhi := math.Tan(phi)
+
+	n := wgs84A / math.Sqrt(1-e2*sinPhi*sinPhi)
+	t := tanPhi * tanPhi
+	c := ep2 * cosPhi * cosPhi
+	a := cosPhi * (lng - float64(zone*6-183)) * math.Pi / 180
+	m := wgs84A * ((1-e2/4-3*e2*e2/64-5*e2*e2*e2/256)*phi -
+		(3*e2/8+3*e2*e2/32+45*e2*e2*e2/1024)*math.Sin(2*phi) +
+		(15*e2*e2/256+45*e2*e2*e2/1024)*math.Sin(4*phi) -
+		(35*e2*e2*e2/3072)*math.Sin(6*phi))
+
+	easting = utmScale*n*(a+(1-t+c)*a*a*a/6+(5-18*t+t*t+72*c-58*ep2)*math.Pow(a, 5)/120) + utmFalseEasting
+	northing = utmScale * (m + n*tanPhi*(a*a/2+(5-t+9*c+4*c*c)*math.Pow(a, 4)/24+(61-58*t+t*t+600*c-330*ep2)*math.Pow(a, 6)/720))
+	if lat < 0 {
+		northing += utmFalseNorthing
+	}
+	return easting, northing
+}
+
+// utmInverse unprojects the position in the zone, the inverse of utmForward
+func utmInverse(easting, northing float64, zone int, north bool) (lng, lat float64) {
+	e2 := wgs84F * (2 - wgs84F)
+	ep2 := e2 / (1 - e2)
+	if !north {
+		northing -= utmFalseNorthing
+	}
+
+	mu := northing / utmScale / (wgs84A * (1 - e2/4 - 3*e2*e2/64 - 5*e2*e2*e2/256))
+	e1 := (1 - math.Sqrt(1-e2)) / (1 + math.Sqrt(1-e2))
+	phi1 := mu + (3*e1/2-27*e1*e1*e1/32)*math.Sin(2*mu) +
+		(21*e1*e1/16-55*math.Pow(e1, 4)/32)*math.Sin(4*mu) +
+		(151*e1*e1*e1/96)*math.Sin(6*mu) +
+		(1097*math.Pow(e1, 4)/512)*math.Sin(8*mu)
+
+	sinPhi1, cosPhi1 := math.Sincos(phi1)
+	tanPhi1 := math.Tan(phi1)
+	c1 := ep2 * cosPhi1 * cosPhi1
+	t1 := tanPhi1 * tanPhi1
+	n1 := wgs84A / math.Sqrt(1-e2*sinPhi1*sinPhi1)
+	r1 := wgs84A * (1 - e2) / math.Pow(1-e2*sinPhi1*sinPhi1, 1.5)
+	d := (easting - utmFalseEasting) / (n1 * utmScale)
+
+	phi := phi1 - (n1*tanPhi1/r1)*(d*d/2-
+		(5+3*t1+10*c1-4*c1*c1-9*ep2)*math.Pow(d, 4)/24+
+		(61+90*t1+298*c1+45*t1*t1-252*ep2-3*c1*c1)*math.Pow(d, 6)/720)
+	lambda := (d - (1+2*t1+c1)*d*d*d/6 + (5-2*c1+28*t1-3*c1*c1+8*ep2+24*t1*t1)*math.Pow(d, 5)/120) / cosPhi1
+
+	return WrapLng(float64(zone*6-183) + lambda*180/math.Pi), phi * 180 / math.Pi
+}
@@ -0,0 +1,180 @@
+package geohash
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestPoint_UTM(t *testing.T) {
+	tests := []struct {
+		name    string
+		p       *Point
+		want    UTM
+		wantErr error
+	}{
+		{
+			name: "TestPoint_UTM 1",
+			p:    NewPoint(7.5, 51.2, nil),
+			want: UTM{Zone: 32, Band: 'U', Easting: 395201.3103811303, Northing: 5673135.241182375},
+		},
+		{
+			name: "TestPoint_UTM 2",
+			p:    NewPoint(-58.38, -34.6, nil),
+			want: UTM{Zone: 21, Band: 'H', Easting: 373458.6072, Northing: 6170448.5113},
+		},
+		{
+			name: "TestPoint_UTM 3",
+			p:    NewPoint(5, 60, nil),
+			want: UTM{Zone: 32, Band: 'V', Easting: 276979.9264, Northing: 6658157.2032},
+		},
+		{
+			name: "TestPoint_UTM 4",
+			p:    NewPoint(15, 78, nil),
+			want: UTM{Zone: 33, Band: 'X', Easting: 500000, Northing: 8658369.5867},
+		},
+		{
+			name:    "TestPoint_UTM 5",
+			p:       NewPoint(0, 85, nil),
+			wantErr: ErrInvalidPoint,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.p.UTM()
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("UTM() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got.Zone != tt.want.Zone || got.Band != tt.want.Band || math.Abs(got.Easting-tt.want.Easting) > 1e-3 || math.Abs(got.Northing-tt.want.Northing) > 1e-3 {
+				t.Errorf("UTM() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUTM_Point(t *testing.T) {
+	for _, p := range []*Point{NewPoint(7.5, 51.2, nil), NewPoint(121.506377, 31.245105, nil), NewPoint(-58.38, -34.6, nil), NewPoint(179.9, -79.9, nil)} {
+		u, _ := p.UTM()
+		t.Run("TestUTM_Point "+u.String(), func(t *testing.T) {
+			got, err := u.Point()
+			if err != nil {
+				t.Fatalf("Point() error = %v", err)
+			}
+			if math.Abs(got.Lng-p.Lng) > 1e-8 || math.Abs(got.Lat-p.Lat) > 1e-8 {
+				t.Errorf("Point() = %v, want %v", got, p)
+			}
+		})
+	}
+	t.Run("TestUTM_Point invalid", func(t *testing.T) {
+		if _, err := (UTM{Zone: 61, Band: 'U', Easting: 500000, Northing: 0}).Point(); !errors.Is(err, ErrInvalidGridReference) {
+			t.Errorf("Point() error = %v, want %v", err, ErrInvalidGridReference)
+		}
+	})
+}
+
+func TestPoint_MGRS(t *testing.T) {
+	tests := []struct {
+		name      string
+		p         *Point
+		precision int
+		want      string
+		wantErr   bool
+	}{
+		{
+			name:      "TestPoint_MGRS 1",
+			p:         NewPoint(-93, 42, nil),
+			precision: 5,
+			want:      "15TWG0000049776",
+		},
+		{
+			name:      "TestPoint_MGRS 2",
+			p:         NewPoint(121.506377, 31.245105, "东方明珠"),
+			precision: 3,
+			want:      "51RUQ577577",
+		},
+		{
+			name:      "TestPoint_MGRS 3",
+			p:         NewPoint(7.5, 51.2, nil),
+			precision: 0,
+			want:      "32ULB",
+		},
+		{
+			name:      "TestPoint_MGRS 4",
+			p:         NewPoint(7.5, 51.2, nil),
+			precision: 6,
+			wantErr:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.p.MGRS(tt.precision)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("MGRS() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("MGRS() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseMGRS(t *testing.T) {
+	tests := []struct {
+		name      string
+		mgrs      string
+		want      *Point
+		tolerance Distance
+	}{
+		{
+			name:      "TestParseMGRS 1",
+			mgrs:      "15TWG0000049776",
+			want:      NewPoint(-93, 42, nil),
+			tolerance: Meter,
+		},
+		{
+			name:      "TestParseMGRS 2",
+			mgrs:      "51R UQ 57768 57727",
+			want:      NewPoint(121.506377, 31.245105, "东方明珠"),
+			tolerance: Meter,
+		},
+		{
+			name:      "TestParseMGRS 3",
+			mgrs:      "21hub7345870448",
+			want:      NewPoint(-58.38, -34.6, nil),
+			tolerance: Meter,
+		},
+		{
+			name:      "TestParseMGRS 4",
+			mgrs:      "33XWG0000058369",
+			want:      NewPoint(15, 78, nil),
+			tolerance: Meter,
+		},
+		{
+			name:      "TestParseMGRS 5",
+			mgrs:      "32ULB",
+			want:      NewPoint(7.5, 51.2, nil),
+			tolerance: 100 * Kilometer,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseMGRS(tt.mgrs)
+			if err != nil {
+				t.Fatalf("ParseMGRS() error = %v", err)
+			}
+			if d := got.DistanceTo(tt.want); d > tt.tolerance {
+				t.Errorf("ParseMGRS() = %v, %v from %v", got, d, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseMGRS_invalid(t *testing.T) {
+	for _, mgrs := range []string{"", "61TWG00", "15IWG00", "15TWI00", "15TWG000", "15TWG00a0", "T"} {
+		t.Run("TestParseMGRS_invalid "+mgrs, func(t *testing.T) {
+			if _, err := ParseMGRS(mgrs); !errors.Is(err, ErrInvalidGridReference) {
+				t.Errorf("ParseMGRS() error = %v, want %v", err, ErrInvalidGridReference)
+			}
+		})
+	}
+}
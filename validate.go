@@ -0,0 +1,59 @@
+package geohash
+
+import (
+	"fmt"
+	"math"
+)
+
+// PointError reports the coordinates of an invalid Point.
+type PointError struct {
+	Lng, Lat float64
+	Reason   string
+}
+
+func (e *PointError) Error() string {
+	return fmt.Sprintf("invalid point (%v, %v): %s", e.Lng, e.Lat, e.Reason)
+}
+
+// Unwrap makes a *PointError match ErrInvalidPoint.
+func (e *PointError) Unwrap() error {
+	return ErrInvalidPoint
+}
+
+// NewValidPoint creates a Point like NewPoint, but returns a *PointError for NaN, infinite or out of range coordinates
+// instead of letting them be encoded into an edge cell. Wrap the longitude with WrapLng first to accept any finite one.
+func NewValidPoint(lng, lat float64, val any) (*Point, error) {
+	p := NewPoint(lng, lat, val)
+	if err := p.Validate(); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// Validate returns a *PointError if the longitude is not in [-180, 180] or the latitude is not in [-90, 90].
+func (p *Point) Validate() error {
+	if p == nil {
+		return &PointError{Reason: "nil point"}
+	}
+
+	switch {
+	case math.IsNaN(p.Lng) || math.IsNaN(p.Lat):
+		return &PointError{Lng: p.Lng, Lat: p.Lat, Reason: "NaN coordinate"}
+	case math.IsInf(p.Lng, 0) || math.IsInf(p.Lat, 0):
+		return &PointError{Lng: p.Lng, Lat: p.Lat, Reason: "infinite coordinate"}
+	case p.Lng < minLng || p.Lng > maxLng:
+		return &PointError{Lng: p.Lng, Lat: p.Lat, Reason: "longitude out of range"}
+	case p.Lat < minLat || p.Lat > maxLat:
+		return &PointError{Lng: p.Lng, Lat: p.Lat, Reason: "latitude out of range"}
+	default:
+		return nil
+	}
+}
+
+// WrapLng wraps the longitude into [-180, 180), like 540 into -180 and 190 into -170.
+func WrapLng(lng float64) float64 {
+	if lng >= minLng && lng < maxLng {
+		return lng
+	}
+	return math.Mod(math.Mod(lng-minLng, 360)+360, 360) + minLng
+}
@@ -0,0 +1,115 @@
+package geohash
+
+import (
+	"errors"
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestNewValidPoint(t *testing.T) {
+	tests := []struct {
+		name     string
+		lng, lat float64
+		want     *Point
+		wantErr  error
+	}{
+		{
+			name: "TestNewValidPoint 1",
+			lng:  121.506377,
+			lat:  31.245105,
+			want: NewPoint(121.506377, 31.245105, nil),
+		},
+		{
+			name: "TestNewValidPoint 2",
+			lng:  180,
+			lat:  -90,
+			want: NewPoint(180, -90, nil),
+		},
+		{
+			name:    "TestNewValidPoint 3",
+			lng:     math.NaN(),
+			lat:     0,
+			wantErr: &PointError{Lng: math.NaN(), Lat: 0, Reason: "NaN coordinate"},
+		},
+		{
+			name:    "TestNewValidPoint 4",
+			lng:     0,
+			lat:     math.Inf(1),
+			wantErr: &PointError{Lng: 0, Lat: math.Inf(1), Reason: "infinite coordinate"},
+		},
+		{
+			name:    "TestNewValidPoint 5",
+			lng:     540,
+			lat:     0,
+			wantErr: &PointError{Lng: 540, Lat: 0, Reason: "longitude out of range"},
+		},
+		{
+			name:    "TestNewValidPoint 6",
+			lng:     0,
+			lat:     200,
+			wantErr: &PointError{Lng: 0, Lat: 200, Reason: "latitude out of range"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewValidPoint(tt.lng, tt.lat, nil)
+			if (err == nil) != (tt.wantErr == nil) || err != nil && err.Error() != tt.wantErr.Error() {
+				t.Fatalf("NewValidPoint() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			var pointErr *PointError
+			if err != nil && (!errors.As(err, &pointErr) || !errors.Is(err, ErrInvalidPoint)) {
+				t.Errorf("NewValidPoint() error = %T, want *PointError matching ErrInvalidPoint", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("NewValidPoint() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWrapLng(t *testing.T) {
+	tests := []struct {
+		name string
+		lng  float64
+		want float64
+	}{
+		{
+			name: "TestWrapLng 1",
+			lng:  121.506377,
+			want: 121.506377,
+		},
+		{
+			name: "TestWrapLng 2",
+			lng:  180,
+			want: -180,
+		},
+		{
+			name: "TestWrapLng 3",
+			lng:  190,
+			want: -170,
+		},
+		{
+			name: "TestWrapLng 4",
+			lng:  540,
+			want: -180,
+		},
+		{
+			name: "TestWrapLng 5",
+			lng:  -190,
+			want: 170,
+		},
+		{
+			name: "TestWrapLng 6",
+			lng:  -900,
+			want: 180 - 360,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := WrapLng(tt.lng); math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("WrapLng() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
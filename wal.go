@@ -0,0 +1,503 @@
+package geohash
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// SyncPolicy decides when the write-ahead log is flushed to the disk by fsync:
+// SyncAlways after every record, SyncNever leaves it to the OS,
+// and SyncEvery periodically, which may lose the writes of the last interval on a crash.
+type SyncPolicy time.Duration
+
+const (
+	SyncAlways SyncPolicy = 0
+	SyncNever  SyncPolicy = -1
+)
+
+func SyncEvery(interval time.Duration) SyncPolicy {
+	if interval <= 0 {
+		return SyncAlways
+	}
+	return SyncPolicy(interval)
+}
+
+const (
+	walFileName      = "trie.wal"
+	snapshotFileName = "trie.snapshot"
+
+	walHeaderLen = 8
+)
+
+const (
+	walOpPut byte = iota + 1
+	walOpDelete
+	walOpMove
+	walOpPutWithTTL
+)
+
+type (
+	// DurableTrie is a Trie whose writes are recorded in a write-ahead log before they are applied.
+	// Checkpoint persists the Trie and truncates the log, OpenDurableTrie recovers every acknowledged write
+	// from the latest checkpoint and the log.
+	// Every write method of the Trie is logged, except RemoveExpired, which needs no log as the deadlines are logged.
+	// Writing through the embedded Trie field itself bypasses the log.
+	DurableTrie struct {
+		*Trie
+		dir string
+		wal *wal
+
+		mu sync.Mutex // keeps the order of the log the same as the order of the writes
+	}
+
+	// wal is an append-only log, every record is framed as
+	//
+	//	length   uint32
+	//	checksum uint32 CRC-32 (IEEE) of data
+	//	data     [length]byte
+	wal struct {
+		file    walFile
+		policy  SyncPolicy
+		offset  int64 // the end of the last acknowledged record
+		syncErr error // the last error of the periodic fsync, reported by the next append
+		failed  error // set once a failed append cannot be rolled back, every later append returns it
+
+		stop chan struct{}
+		done chan struct{}
+
+		sync.Mutex
+	}
+
+	// walFile is the file of a wal, *os.File in production
+	walFile interface {
+		io.WriteSeeker
+		Sync() error
+		Truncate(size int64) error
+		Close() error
+	}
+)
+
+// OpenDurableTrie loads the checkpoint in dir, replays the write-ahead log on it,
+// and then keeps logging to dir with the SyncPolicy.
+func OpenDurableTrie(dir string, policy SyncPolicy, opts ...TrieOption) (*DurableTrie, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+
+	d := &DurableTrie{Trie: NewTrie(opts...), dir: dir}
+	if err := d.loadCheckpoint(); err != nil {
+		return nil, err
+	}
+
+	w, err := openWAL(filepath.Join(dir, walFileName), policy, d.replay)
+	if err != nil {
+		return nil, err
+	}
+	d.wal = w
+	return d, nil
+}
+
+func (d *DurableTrie) Put(point *Point) error {
+	if d == nil {
+		return ErrNilTrie
+	}
+	// the log holds the points in the datum of the Trie, which replay reads them in
+	point, err := d.normalizeValid(point)
+	if err != nil {
+		return err
+	}
+
+	record, fw := d.newRecord(walOpPut)
+	fw.writePoint(point)
+	if fw.err != nil {
+		return fw.err
+	}
+	return d.apply(record.Bytes(), func() {
+		d.Trie.Put(point)
+	})
+}
+
+// PutWithTTL logs the absolute deadline of the point, so it still expires on time after a recovery.
+func (d *DurableTrie) PutWithTTL(point *Point, ttl time.Duration) error {
+	if d == nil {
+		return ErrNilTrie
+	}
+	point, err := d.normalizeValid(point)
+	if err != nil {
+		return err
+	}
+
+	deadline := deadlineOf(ttl)
+	record, fw := d.newRecord(walOpPutWithTTL)
+	fw.writePoint(point)
+	fw.writeUvarint(uint64(deadline))
+	if fw.err != nil {
+		return fw.err
+	}
+	return d.apply(record.Bytes(), func() {
+		d.Trie.putUntil(point, deadline)
+	})
+}
+
+func (d *DurableTrie) Delete(geohash Geohash) (bool, error) {
+	if d == nil || !geohash.valid() {
+		return false, nil
+	}
+
+	record, fw := d.newRecord(walOpDelete)
+	fw.writeBytes([]byte(geohash))
+	var ok bool
+	err := d.apply(record.Bytes(), func() {
+		ok = d.Trie.Delete(geohash)
+	})
+	return ok, err
+}
+
+// Remove is Delete returning ErrInvalidGeohash for an invalid geohash and ErrNotFound for an absent box.
+func (d *DurableTrie) Remove(geohash Geohash) error {
+	if !geohash.valid() {
+		return ErrInvalidGeohash
+	}
+	ok, err := d.Delete(geohash)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (d *DurableTrie) Move(from, to *Point) (bool, error) {
+	if d == nil || from == nil || to == nil {
+		return false, nil
+	}
+	to, err := d.normalizeValid(to)
+	if err != nil {
+		return false, err
+	}
+	from = d.normalize(from)
+
+	record, fw := d.newRecord(walOpMove)
+	fw.writePoint(from)
+	fw.writePoint(to)
+	if fw.err != nil {
+		return false, fw.err
+	}
+	var ok bool
+	err = d.apply(record.Bytes(), func() {
+		ok = d.Trie.Move(from, to)
+	})
+	return ok, err
+}
+
+// Checkpoint atomically replaces the checkpoint in dir with the current Trie and truncates the log.
+// Writes are blocked until it returns.
+func (d *DurableTrie) Checkpoint() error {
+	if d == nil {
+		return ErrNilTrie
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	return d.checkpoint(d.Trie)
+}
+
+// ReadFrom replaces the points like Trie.ReadFrom, the points read are checkpointed before they are applied.
+func (d *DurableTrie) ReadFrom(r io.Reader) (int64, error) {
+	if d == nil {
+		return 0, ErrNilTrie
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	loaded := &Trie{root: &node{}, codec: d.codec, datum: d.datum}
+	n, err := loaded.ReadFrom(r)
+	if err != nil {
+		return n, err
+	}
+	if err = d.checkpoint(loaded); err != nil {
+		return n, err
+	}
+	d.Trie.replace(loaded)
+	return n, nil
+}
+
+// Close flushes and closes the write-ahead log, the DurableTrie must not be written afterwards.
+func (d *DurableTrie) Close() error {
+	if d == nil {
+		return nil
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	return d.wal.close()
+}
+
+// checkpoint writes t as the checkpoint in dir and truncates the log
+func (d *DurableTrie) checkpoint(t *Trie) error {
+	tmp, err := os.CreateTemp(d.dir, snapshotFileName+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err = t.WriteTo(tmp); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err = tmp.Sync(); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err = tmp.Close(); err != nil {
+		return err
+	}
+	if err = os.Rename(tmp.Name(), filepath.Join(d.dir, snapshotFileName)); err != nil {
+		return err
+	}
+	if err = syncDir(d.dir); err != nil {
+		return err
+	}
+
+	return d.wal.truncate()
+}
+
+// newRecord returns the buffer of a log record of op and the formatWriter to encode its operands
+func (d *DurableTrie) newRecord(op byte) (*bytes.Buffer, *formatWriter) {
+	record := &bytes.Buffer{}
+	record.WriteByte(op)
+	return record, &formatWriter{w: record, codec: d.payloadCodec()}
+}
+
+// apply logs the record and then applies the write, the write is not applied if logging fails
+func (d *DurableTrie) apply(record []byte, write func()) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if err := d.wal.append(record); err != nil {
+		return err
+	}
+	write()
+	return nil
+}
+
+func (d *DurableTrie) loadCheckpoint() error {
+	f, err := os.Open(filepath.Join(d.dir, snapshotFileName))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	_, err = d.Trie.ReadFrom(f)
+	return err
+}
+
+// replay applies one record of the log to the Trie
+func (d *DurableTrie) replay(data []byte) error {
+	fr := &formatReader{r: bytes.NewReader(data), codec: d.payloadCodec(), datum: d.datum}
+	op := fr.readBytes(1)
+	if fr.err != nil {
+		return fr.err
+	}
+
+	switch op[0] {
+	case walOpPut:
+		point := fr.readPoint()
+		if fr.err == nil {
+			d.Trie.Put(point)
+		}
+	case walOpPutWithTTL:
+		point := fr.readPoint()
+		deadline := int64(fr.readUvarint())
+		if fr.err == nil {
+			d.Trie.putUntil(point, deadline)
+		}
+	case walOpDelete:
+		geohash := Geohash(fr.readBytes(geohashLen))
+		if fr.err == nil {
+			d.Trie.Delete(geohash)
+		}
+	case walOpMove:
+		from, to := fr.readPoint(), fr.readPoint()
+		if fr.err == nil {
+			d.Trie.Move(from, to)
+		}
+	default:
+		return fmt.Errorf("%w: unknown operation %d", ErrCorruptData, op[0])
+	}
+	return fr.err
+}
+
+// openWAL opens the log at path and passes its records to replay in order.
+// A torn or corrupt tail left by a crash is never acknowledged, so it is truncated.
+func openWAL(path string, policy SyncPolicy, replay func(data []byte) error) (*wal, error) {
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0o644)
+	if err != nil {
+		return nil, err
+	}
+
+	offset, err := replayWAL(file, replay)
+	if err == nil {
+		err = file.Truncate(offset)
+	}
+	if err == nil {
+		_, err = file.Seek(offset, io.SeekStart)
+	}
+	if err != nil {
+		_ = file.Close()
+		return nil, err
+	}
+
+	w := &wal{file: file, policy: policy, offset: offset}
+	if policy > 0 {
+		w.stop = make(chan struct{})
+		w.done = make(chan struct{})
+		go w.syncPeriodically(time.Duration(policy))
+	}
+	return w, nil
+}
+
+// replayWAL returns the offset after the last intact record
+func replayWAL(r io.Reader, replay func(data []byte) error) (int64, error) {
+	br := bufio.NewReader(r)
+	header := make([]byte, walHeaderLen)
+	var offset int64
+	for {
+		if _, err := io.ReadFull(br, header); err != nil {
+			return offset, nil
+		}
+		length := binary.LittleEndian.Uint32(header)
+		data := bytes.Buffer{}
+		if _, err := io.CopyN(&data, br, int64(length)); err != nil {
+			return offset, nil
+		}
+		if crc32.ChecksumIEEE(data.Bytes()) != binary.LittleEndian.Uint32(header[4:]) {
+			return offset, nil
+		}
+
+		if err := replay(data.Bytes()); err != nil {
+			return offset, err
+		}
+		offset += walHeaderLen + int64(length)
+	}
+}
+
+func (w *wal) append(data []byte) error {
+	w.Lock()
+	defer w.Unlock()
+
+	if w.failed != nil {
+		return w.failed
+	}
+	if w.syncErr != nil {
+		err := w.syncErr
+		w.syncErr = nil
+		return err
+	}
+
+	record := make([]byte, walHeaderLen, walHeaderLen+len(data))
+	binary.LittleEndian.PutUint32(record, uint32(len(data)))
+	binary.LittleEndian.PutUint32(record[4:], crc32.ChecksumIEEE(data))
+	record = append(record, data...)
+	if _, err := w.file.Write(record); err != nil {
+		return w.rollback(err)
+	}
+	if w.policy == SyncAlways {
+		if err := w.file.Sync(); err != nil {
+			return w.rollback(err)
+		}
+	}
+
+	w.offset += int64(len(record))
+	return nil
+}
+
+// rollback cuts the record that failed to be appended off the log, otherwise a torn record would hide
+// the acknowledged ones after it from the replay, and a whole one would replay a write reported as failed
+func (w *wal) rollback(err error) error {
+	if truncateErr := w.file.Truncate(w.offset); truncateErr != nil {
+		w.failed = fmt.Errorf("write-ahead log failed: %w", errors.Join(err, truncateErr))
+		return w.failed
+	}
+	if _, seekErr := w.file.Seek(w.offset, io.SeekStart); seekErr != nil {
+		w.failed = fmt.Errorf("write-ahead log failed: %w", errors.Join(err, seekErr))
+		return w.failed
+	}
+	return err
+}
+
+func (w *wal) truncate() error {
+	w.Lock()
+	defer w.Unlock()
+
+	if err := w.file.Truncate(0); err != nil {
+		return err
+	}
+	if _, err := w.file.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+	w.offset = 0
+	return w.file.Sync()
+}
+
+func (w *wal) close() error {
+	if w.stop != nil {
+		close(w.stop)
+		<-w.done
+	}
+
+	w.Lock()
+	defer w.Unlock()
+
+	if err := w.file.Sync(); err != nil {
+		_ = w.file.Close()
+		return err
+	}
+	return w.file.Close()
+}
+
+func (w *wal) syncPeriodically(interval time.Duration) {
+	defer close(w.done)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-w.stop:
+			return
+		case <-ticker.C:
+			w.Lock()
+			if err := w.file.Sync(); err != nil {
+				w.syncErr = err
+			}
+			w.Unlock()
+		}
+	}
+}
+
+// syncDir makes a rename in dir durable
+func syncDir(dir string) error {
+	f, err := os.Open(dir)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	return f.Sync()
+}
@@ -0,0 +1,351 @@
+package geohash
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestOpenDurableTrie(t1 *testing.T) {
+	dir := t1.TempDir()
+	p1 := NewPoint(13.361389, 38.115556, "Palermo")
+	p2 := NewPoint(121.506377, 31.245105, "东方明珠")
+	p3 := NewPoint(121.4871639, 31.2388556, "上海和平饭店")
+
+	d, err := OpenDurableTrie(dir, SyncAlways)
+	if err != nil {
+		t1.Fatalf("OpenDurableTrie() error = %v", err)
+	}
+	if err = d.Put(p1); err != nil {
+		t1.Fatalf("Put() error = %v", err)
+	}
+	if err = d.Put(p2); err != nil {
+		t1.Fatalf("Put() error = %v", err)
+	}
+	if err = d.Checkpoint(); err != nil {
+		t1.Fatalf("Checkpoint() error = %v", err)
+	}
+	if ok, err := d.Move(p2, p3); !ok || err != nil {
+		t1.Fatalf("Move() = %v, error = %v", ok, err)
+	}
+	if ok, err := d.Delete(p1.Geohash()); !ok || err != nil {
+		t1.Fatalf("Delete() = %v, error = %v", ok, err)
+	}
+	// simulate a crash: the log is reopened before it is closed
+	defer d.Close()
+	want := d.Trie.root.dfs()
+
+	t1.Run("TestOpenDurableTrie recover", func(t1 *testing.T) {
+		got, err := OpenDurableTrie(dir, SyncAlways)
+		if err != nil {
+			t1.Fatalf("OpenDurableTrie() error = %v", err)
+		}
+		defer got.Close()
+		if !reflect.DeepEqual(got.root.dfs(), want) {
+			t1.Errorf("OpenDurableTrie() got = %v, want %v", got.root.dfs(), want)
+		}
+	})
+	t1.Run("TestOpenDurableTrie torn tail", func(t1 *testing.T) {
+		f, err := os.OpenFile(filepath.Join(dir, walFileName), os.O_WRONLY|os.O_APPEND, 0o644)
+		if err != nil {
+			t1.Fatalf("OpenFile() error = %v", err)
+		}
+		_, _ = f.Write([]byte{42, 0, 0, 0, 1, 2})
+		_ = f.Close()
+
+		got, err := OpenDurableTrie(dir, SyncNever)
+		if err != nil {
+			t1.Fatalf("OpenDurableTrie() error = %v", err)
+		}
+		if !reflect.DeepEqual(got.root.dfs(), want) {
+			t1.Errorf("OpenDurableTrie() got = %v, want %v", got.root.dfs(), want)
+		}
+		// the torn record is truncated, so the next record is appended right after the intact ones
+		if err = got.Put(p1); err != nil {
+			t1.Fatalf("Put() error = %v", err)
+		}
+		_ = got.Close()
+
+		again, err := OpenDurableTrie(dir, SyncEvery(time.Millisecond))
+		if err != nil {
+			t1.Fatalf("OpenDurableTrie() error = %v", err)
+		}
+		defer again.Close()
+		if _, ok := again.Get(p1.Geohash()); !ok {
+			t1.Errorf("Get() = %v, want %v", ok, true)
+		}
+	})
+}
+
+func TestDurableTrie_PutWithTTL(t1 *testing.T) {
+	dir := t1.TempDir()
+	p1 := NewPoint(13.361389, 38.115556, "Palermo")
+	p2 := NewPoint(121.506377, 31.245105, "东方明珠")
+	d, err := OpenDurableTrie(dir, SyncAlways)
+	if err != nil {
+		t1.Fatalf("OpenDurableTrie() error = %v", err)
+	}
+	defer d.Close()
+	if err = d.PutWithTTL(p1, time.Nanosecond); err != nil {
+		t1.Fatalf("PutWithTTL() error = %v", err)
+	}
+	if err = d.PutWithTTL(p2, time.Hour); err != nil {
+		t1.Fatalf("PutWithTTL() error = %v", err)
+	}
+	t1.Run("TestDurableTrie_PutWithTTL", func(t1 *testing.T) {
+		got, err := OpenDurableTrie(dir, SyncAlways)
+		if err != nil {
+			t1.Fatalf("OpenDurableTrie() error = %v", err)
+		}
+		defer got.Close()
+		if got := got.RemoveExpired(); got != 1 {
+			t1.Errorf("RemoveExpired() = %v, want %v", got, 1)
+		}
+		if _, ok := got.Get(p2.Geohash()); !ok {
+			t1.Errorf("Get() = %v, want %v", ok, true)
+		}
+	})
+}
+
+func TestDurableTrie_Checkpoint(t1 *testing.T) {
+	dir := t1.TempDir()
+	d, err := OpenDurableTrie(dir, SyncAlways)
+	if err != nil {
+		t1.Fatalf("OpenDurableTrie() error = %v", err)
+	}
+	defer d.Close()
+	for i := 0; i < 10; i++ {
+		if err = d.Put(NewPoint(float64(i), float64(i), i)); err != nil {
+			t1.Fatalf("Put() error = %v", err)
+		}
+	}
+	t1.Run("TestDurableTrie_Checkpoint", func(t1 *testing.T) {
+		if err := d.Checkpoint(); err != nil {
+			t1.Fatalf("Checkpoint() error = %v", err)
+		}
+		info, err := os.Stat(filepath.Join(dir, walFileName))
+		if err != nil || info.Size() != 0 {
+			t1.Errorf("Checkpoint() log = %v, error = %v", info, err)
+		}
+		got := NewTrie()
+		f, err := os.Open(filepath.Join(dir, snapshotFileName))
+		if err != nil {
+			t1.Fatalf("Open() error = %v", err)
+		}
+		defer f.Close()
+		if _, err = got.ReadFrom(f); err != nil || got.Count() != 10 {
+			t1.Errorf("ReadFrom() = %v, error = %v", got.Count(), err)
+		}
+	})
+}
+
+func TestDurableTrie_Remove(t1 *testing.T) {
+	dir := t1.TempDir()
+	p := NewPoint(13.361389, 38.115556, "Palermo")
+	d, err := OpenDurableTrie(dir, SyncAlways)
+	if err != nil {
+		t1.Fatalf("OpenDurableTrie() error = %v", err)
+	}
+	_ = d.Put(p)
+	if err = d.Remove(p.Geohash()); err != nil {
+		t1.Fatalf("Remove() error = %v", err)
+	}
+	if err = d.Remove(p.Geohash()); !errors.Is(err, ErrNotFound) {
+		t1.Errorf("Remove() error = %v, want %v", err, ErrNotFound)
+	}
+	_ = d.Close()
+
+	t1.Run("TestDurableTrie_Remove", func(t1 *testing.T) {
+		got, err := OpenDurableTrie(dir, SyncAlways)
+		if err != nil {
+			t1.Fatalf("OpenDurableTrie() error = %v", err)
+		}
+		defer got.Close()
+		if count := got.CountPoints(); count != 0 {
+			t1.Errorf("CountPoints() = %v, want %v", count, 0)
+		}
+	})
+}
+
+func TestDurableTrie_ReadFrom(t1 *testing.T) {
+	dir := t1.TempDir()
+	src := NewTrie()
+	_ = src.Put(NewPoint(13.361389, 38.115556, "Palermo"))
+	_ = src.Put(NewPoint(121.506377, 31.245105, "东方明珠"))
+	var buf bytes.Buffer
+	if _, err := src.WriteTo(&buf); err != nil {
+		t1.Fatalf("WriteTo() error = %v", err)
+	}
+
+	d, err := OpenDurableTrie(dir, SyncAlways)
+	if err != nil {
+		t1.Fatalf("OpenDurableTrie() error = %v", err)
+	}
+	_ = d.Put(NewPoint(116.404, 39.915, "天安门"))
+	if _, err = d.ReadFrom(&buf); err != nil {
+		t1.Fatalf("ReadFrom() error = %v", err)
+	}
+	_ = d.Close()
+
+	t1.Run("TestDurableTrie_ReadFrom", func(t1 *testing.T) {
+		got, err := OpenDurableTrie(dir, SyncAlways)
+		if err != nil {
+			t1.Fatalf("OpenDurableTrie() error = %v", err)
+		}
+		defer got.Close()
+		if !reflect.DeepEqual(got.root.dfs(), src.root.dfs()) {
+			t1.Errorf("OpenDurableTrie() got = %v, want %v", got.root.dfs(), src.root.dfs())
+		}
+	})
+}
+
+func TestDurableTrie_datum(t1 *testing.T) {
+	dir := t1.TempDir()
+	gps := NewPoint(121.506377, 31.245105, "东方明珠")
+	want := gps.ToDatum(GCJ02)
+
+	d, err := OpenDurableTrie(dir, SyncAlways, WithDatum(GCJ02))
+	if err != nil {
+		t1.Fatalf("OpenDurableTrie() error = %v", err)
+	}
+	if err = d.Put(gps); err != nil {
+		t1.Fatalf("Put() error = %v", err)
+	}
+	d.Close()
+
+	t1.Run("TestDurableTrie_datum", func(t1 *testing.T) {
+		got, err := OpenDurableTrie(dir, SyncAlways, WithDatum(GCJ02))
+		if err != nil {
+			t1.Fatalf("OpenDurableTrie() error = %v", err)
+		}
+		defer got.Close()
+
+		box, ok := got.Get(want.Geohash())
+		if !ok || !reflect.DeepEqual(box.PointSet[want.key()], want) {
+			t1.Errorf("Get() = %v, want %v", box, want)
+		}
+	})
+}
+
+// faultyFile fails the operations whose error is set, a failed write still writes half of the data
+type faultyFile struct {
+	*os.File
+	writeErr, syncErr, truncateErr error
+}
+
+func (f *faultyFile) Write(p []byte) (int, error) {
+	if f.writeErr != nil {
+		n, _ := f.File.Write(p[:len(p)/2])
+		return n, f.writeErr
+	}
+	return f.File.Write(p)
+}
+
+func (f *faultyFile) Sync() error {
+	if f.syncErr != nil {
+		return f.syncErr
+	}
+	return f.File.Sync()
+}
+
+func (f *faultyFile) Truncate(size int64) error {
+	if f.truncateErr != nil {
+		return f.truncateErr
+	}
+	return f.File.Truncate(size)
+}
+
+func TestDurableTrie_appendError(t1 *testing.T) {
+	dir := t1.TempDir()
+	injected := errors.New("injected")
+	points := []*Point{
+		NewPoint(13.361389, 38.115556, "Palermo"),
+		NewPoint(121.506377, 31.245105, "东方明珠"),
+		NewPoint(121.4871639, 31.2388556, "上海和平饭店"),
+		NewPoint(116.404, 39.915, "天安门"),
+	}
+
+	d, err := OpenDurableTrie(dir, SyncAlways)
+	if err != nil {
+		t1.Fatalf("OpenDurableTrie() error = %v", err)
+	}
+	f := &faultyFile{File: d.wal.file.(*os.File)}
+	d.wal.file = f
+	if err = d.Put(points[0]); err != nil {
+		t1.Fatalf("Put() error = %v", err)
+	}
+	f.writeErr = injected
+	if err = d.Put(points[1]); !errors.Is(err, injected) {
+		t1.Fatalf("Put() error = %v, want %v", err, injected)
+	}
+	f.writeErr, f.syncErr = nil, injected
+	if err = d.Put(points[2]); !errors.Is(err, injected) {
+		t1.Fatalf("Put() error = %v, want %v", err, injected)
+	}
+	f.syncErr = nil
+	if err = d.Put(points[3]); err != nil {
+		t1.Fatalf("Put() error = %v", err)
+	}
+	_ = d.Close()
+
+	t1.Run("TestDurableTrie_appendError recover", func(t1 *testing.T) {
+		got, err := OpenDurableTrie(dir, SyncAlways)
+		if err != nil {
+			t1.Fatalf("OpenDurableTrie() error = %v", err)
+		}
+		defer got.Close()
+		for i, p := range points {
+			// only the acknowledged writes are recovered, including the one after the failures
+			if _, ok := got.Get(p.Geohash()); ok != (i == 0 || i == 3) {
+				t1.Errorf("Get(%v) = %v, want %v", p, ok, !ok)
+			}
+		}
+	})
+	t1.Run("TestDurableTrie_appendError failed", func(t1 *testing.T) {
+		got, err := OpenDurableTrie(t1.TempDir(), SyncAlways)
+		if err != nil {
+			t1.Fatalf("OpenDurableTrie() error = %v", err)
+		}
+		defer got.Close()
+		f := &faultyFile{File: got.wal.file.(*os.File), writeErr: injected, truncateErr: injected}
+		got.wal.file = f
+		if err = got.Put(points[0]); !errors.Is(err, injected) {
+			t1.Fatalf("Put() error = %v, want %v", err, injected)
+		}
+		// the torn record could not be cut off, so the log refuses to append after it
+		f.writeErr, f.truncateErr = nil, nil
+		if err = got.Put(points[1]); !errors.Is(err, injected) {
+			t1.Errorf("Put() error = %v, want %v", err, injected)
+		}
+	})
+}
+
+func TestSyncEvery(t *testing.T) {
+	tests := []struct {
+		name     string
+		interval time.Duration
+		want     SyncPolicy
+	}{
+		{
+			name:     "TestSyncEvery 1",
+			interval: 0,
+			want:     SyncAlways,
+		},
+		{
+			name:     "TestSyncEvery 2",
+			interval: time.Second,
+			want:     SyncPolicy(time.Second),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := SyncEvery(tt.interval); got != tt.want {
+				t.Errorf("SyncEvery() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
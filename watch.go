@@ -0,0 +1,170 @@
+package geohash
+
+import (
+	"strings"
+	"sync/atomic"
+)
+
+const (
+	WatchInsert WatchEventType = iota + 1
+	WatchUpdate
+	WatchDelete
+)
+
+type (
+	// Area is a region watched by Trie.Watch, Rect is an Area as well.
+	Area interface {
+		Contains(p *Point) bool
+	}
+
+	WatchEventType uint8
+
+	// WatchEvent reports a change of the points in an Area.
+	// A point moving into the Area is a WatchInsert, out of it a WatchDelete,
+	// and Old is the previous point of a WatchUpdate.
+	WatchEvent struct {
+		Type  WatchEventType
+		Point *Point
+		Old   *Point
+	}
+
+	// Watcher receives the WatchEvent of an Area until it is closed.
+	Watcher struct {
+		trie    *Trie
+		area    Area
+		events  chan WatchEvent
+		dropped atomic.Uint64
+	}
+
+	prefixArea string
+
+	circleArea struct {
+		center *Point
+		radius Distance
+		metric Metric // the Metric of the watched Trie, DistanceTo if nil
+	}
+)
+
+// PrefixArea returns the Area of the geohash prefix.
+func PrefixArea(prefix string) Area {
+	return prefixArea(prefix)
+}
+
+// CircleArea returns the Area within radius of center.
+func CircleArea(center *Point, radius Distance) Area {
+	return circleArea{center: center, radius: radius}
+}
+
+func (a prefixArea) Contains(p *Point) bool {
+	return p != nil && strings.HasPrefix(string(p.Geohash()), string(a))
+}
+
+func (a circleArea) Contains(p *Point) bool {
+	if a.center == nil || p == nil {
+		return false
+	}
+	if a.metric == nil {
+		return a.center.DistanceTo(p) <= a.radius
+	}
+	return a.metric.Distance(a.center, p) <= a.radius
+}
+
+func (e WatchEventType) String() string {
+	switch e {
+	case WatchInsert:
+		return "Insert"
+	case WatchUpdate:
+		return "Update"
+	case WatchDelete:
+		return "Delete"
+	default:
+		return "Unknown"
+	}
+}
+
+// Watch subscribes to the changes in the area, the events are buffered up to buffer.
+// Writers never block on a slow Watcher: the events which do not fit in the buffer are dropped and counted by Dropped.
+// The center of a CircleArea is converted into the datum of the Trie and its radius is measured by the Metric of the Trie,
+// any other area is tested against the points in that datum, see WithDatum and WithMetric.
+func (t *Trie) Watch(area Area, buffer int) *Watcher {
+	if t == nil || area == nil || buffer < 0 {
+		return nil
+	}
+	if circle, ok := area.(circleArea); ok {
+		circle.center, circle.metric = t.normalize(circle.center), t.distanceMetric()
+		area = circle
+	}
+
+	w := &Watcher{trie: t, area: area, events: make(chan WatchEvent, buffer)}
+
+	t.Lock()
+	defer t.Unlock()
+
+	if t.watchers == nil {
+		t.watchers = map[*Watcher]struct{}{}
+	}
+	t.watchers[w] = struct{}{}
+	return w
+}
+
+// Events returns the channel of the events, it is closed by Close.
+func (w *Watcher) Events() <-chan WatchEvent {
+	if w == nil {
+		return nil
+	}
+	return w.events
+}
+
+// Dropped returns the number of events dropped because the buffer was full.
+func (w *Watcher) Dropped() uint64 {
+	if w == nil {
+		return 0
+	}
+	return w.dropped.Load()
+}
+
+// Close unsubscribes the Watcher and closes its channel.
+func (w *Watcher) Close() {
+	if w == nil {
+		return
+	}
+
+	w.trie.Lock()
+	defer w.trie.Unlock()
+
+	if _, ok := w.trie.watchers[w]; ok {
+		delete(w.trie.watchers, w)
+		close(w.events)
+	}
+}
+
+// notify sends the change from old to point to the watchers, the caller must hold the Trie lock.
+// A nil old is an insert and a nil point is a delete.
+func (t *Trie) notify(old, point *Point) {
+	for w := range t.watchers {
+		w.notify(old, point)
+	}
+}
+
+func (w *Watcher) notify(old, point *Point) {
+	wasIn := old != nil && w.area.Contains(old)
+	isIn := point != nil && w.area.Contains(point)
+
+	var e WatchEvent
+	switch {
+	case wasIn && isIn:
+		e = WatchEvent{Type: WatchUpdate, Point: point, Old: old}
+	case isIn:
+		e = WatchEvent{Type: WatchInsert, Point: point}
+	case wasIn:
+		e = WatchEvent{Type: WatchDelete, Point: old}
+	default:
+		return
+	}
+
+	select {
+	case w.events <- e:
+	default:
+		w.dropped.Add(1)
+	}
+}
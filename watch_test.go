@@ -0,0 +1,160 @@
+package geohash
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestTrie_Watch(t1 *testing.T) {
+	t := NewTrie()
+	tower := NewPoint(121.506377, 31.245105, "东方明珠")
+	bund := NewPoint(121.4871639, 31.2388556, "上海和平饭店")
+	palermo := NewPoint(13.361389, 38.115556, "Palermo")
+	w := t.Watch(CircleArea(tower, 1000), 16)
+
+	towerAgain := NewPoint(121.506377, 31.245105, "东方明珠塔")
+	t.Put(tower)
+	t.Put(palermo)
+	t.Put(towerAgain)
+	t.Move(towerAgain, bund)
+	t.Move(bund, tower)
+	t.Delete(tower.Geohash())
+	t1.Run("TestTrie_Watch", func(t1 *testing.T) {
+		want := []WatchEvent{
+			{Type: WatchInsert, Point: tower},
+			{Type: WatchUpdate, Point: towerAgain, Old: tower},
+			{Type: WatchDelete, Point: towerAgain},
+			{Type: WatchInsert, Point: tower},
+			{Type: WatchDelete, Point: tower},
+		}
+		for _, e := range want {
+			if got := <-w.Events(); !reflect.DeepEqual(got, e) {
+				t1.Errorf("Events() = %v, want %v", got, e)
+			}
+		}
+		select {
+		case got := <-w.Events():
+			t1.Errorf("Events() = %v, want none", got)
+		default:
+		}
+	})
+	t1.Run("TestTrie_Watch Close", func(t1 *testing.T) {
+		w.Close()
+		w.Close()
+		if _, ok := <-w.Events(); ok {
+			t1.Errorf("Events() is not closed")
+		}
+		t.Put(tower)
+		if len(t.watchers) != 0 {
+			t1.Errorf("watchers = %v, want empty", t.watchers)
+		}
+	})
+}
+
+func TestTrie_Watch_expired(t1 *testing.T) {
+	t := NewTrie()
+	tower := NewPoint(121.506377, 31.245105, "东方明珠")
+	w := t.Watch(PrefixArea("WTW"), 1)
+	defer w.Close()
+	t.PutWithTTL(tower, time.Nanosecond)
+	time.Sleep(time.Millisecond)
+	t.RemoveExpired()
+	t1.Run("TestTrie_Watch_expired", func(t1 *testing.T) {
+		if got := <-w.Events(); got.Type != WatchInsert {
+			t1.Errorf("Events() = %v, want %v", got.Type, WatchInsert)
+		}
+		if got := w.Dropped(); got != 1 {
+			t1.Errorf("Dropped() = %v, want %v", got, 1)
+		}
+	})
+}
+
+func TestTrie_Watch_datum(t1 *testing.T) {
+	t := NewTrie(WithDatum(GCJ02))
+	tower := NewPoint(121.506377, 31.245105, "东方明珠")
+	w := t.Watch(CircleArea(tower, 20), 1)
+	defer w.Close()
+	// both are in WGS84, the GCJ02 offset of the stored point is hundreds of meters
+	t.Put(NewPoint(121.506377, 31.245105+10/metersPerDegree, "10m"))
+	t1.Run("TestTrie_Watch_datum", func(t1 *testing.T) {
+		select {
+		case got := <-w.Events():
+			if got.Type != WatchInsert {
+				t1.Errorf("Events() = %v, want %v", got.Type, WatchInsert)
+			}
+		default:
+			t1.Errorf("Events() = none, want %v", WatchInsert)
+		}
+	})
+}
+
+func TestTrie_Watch_metric(t1 *testing.T) {
+	t := NewTrie(WithMetric(Manhattan{}))
+	center := NewPoint(0, 0, nil)
+	w := t.Watch(CircleArea(center, 1100), 1)
+	defer w.Close()
+	// about 1022 meters away on the sphere, but 1445 along the axes
+	p := NewPoint(0.0065, 0.0065, nil)
+	t.Put(p)
+	t1.Run("TestTrie_Watch_metric", func(t1 *testing.T) {
+		got, err := t.GetPointsByCircle(center, 1100)
+		if err != nil || len(got) != 0 {
+			t1.Fatalf("GetPointsByCircle() = %v, %v, want none", got, err)
+		}
+		select {
+		case e := <-w.Events():
+			t1.Errorf("Events() = %v, want none", e.Type)
+		default:
+		}
+	})
+}
+
+func TestArea_Contains(t *testing.T) {
+	tower := NewPoint(121.506377, 31.245105, "东方明珠")
+	bund := NewPoint(121.4871639, 31.2388556, "上海和平饭店")
+	tests := []struct {
+		name string
+		area Area
+		p    *Point
+		want bool
+	}{
+		{
+			name: "TestArea_Contains 1",
+			area: PrefixArea("WTW3"),
+			p:    tower,
+			want: true,
+		},
+		{
+			name: "TestArea_Contains 2",
+			area: PrefixArea("SQC"),
+			p:    tower,
+			want: false,
+		},
+		{
+			name: "TestArea_Contains 3",
+			area: CircleArea(tower, 2000),
+			p:    bund,
+			want: true,
+		},
+		{
+			name: "TestArea_Contains 4",
+			area: CircleArea(tower, 1000),
+			p:    bund,
+			want: false,
+		},
+		{
+			name: "TestArea_Contains 5",
+			area: Rect{MinLng: 121, MinLat: 31, MaxLng: 122, MaxLat: 32},
+			p:    bund,
+			want: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.area.Contains(tt.p); got != tt.want {
+				t.Errorf("Contains() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}